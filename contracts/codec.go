@@ -0,0 +1,441 @@
+// Package contracts encodes Go structs into the layouts smart contracts use for their inputs and outputs.
+//
+// Marshal and Unmarshal follow the C++ layout of the contract structs: little endian values, every field aligned to
+// its natural alignment and structs padded to a multiple of their largest alignment. Fields are configured with the
+// qubic tag:
+//
+//	type AddToAskOrderInput struct {
+//		Issuer         types.Identity         // 32 byte public key
+//		AssetName      string `qubic:"asset"` // up to 7 characters stored in an uint64
+//		Price          int64
+//		NumberOfShares int64
+//	}
+//
+// Supported tag options, comma separated, are "id" for a [32]byte public key, "bytes" for a [32]byte array of plain
+// bytes, "asset" for a string or [8]byte asset name, "align=N" to override the alignment of a field and "-" to skip it.
+// types.Identity fields and [32]byte arrays are public keys without a tag, other byte arrays are plain bytes. Blank
+// fields (_) are written as zero padding.
+package contracts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+const tagName = "qubic"
+
+// idAlignment is the alignment of public keys, the m256i of the core made of 4 uint64. It is not the 32 byte alignment
+// of __m256i: RespondedEntity, read as types.AddressInfo, has its m256i siblings at offset 72.
+const idAlignment = 8
+
+var identityType = reflect.TypeOf(types.Identity(""))
+
+type fieldOptions struct {
+	skip  bool
+	id    bool
+	bytes bool
+	asset bool
+	align int
+}
+
+func parseTag(tag string) (fieldOptions, error) {
+	var opts fieldOptions
+	if tag == "" {
+		return opts, nil
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		switch {
+		case option == "-":
+			opts.skip = true
+		case option == "id":
+			opts.id = true
+		case option == "bytes":
+			opts.bytes = true
+		case option == "asset":
+			opts.asset = true
+		case strings.HasPrefix(option, "align="):
+			align, err := strconv.Atoi(strings.TrimPrefix(option, "align="))
+			if err != nil || align <= 0 || align&(align-1) != 0 {
+				return fieldOptions{}, errors.Errorf("invalid alignment in tag option %q", option)
+			}
+			opts.align = align
+		default:
+			return fieldOptions{}, errors.Errorf("unknown tag option %q", option)
+		}
+	}
+
+	if opts.id && opts.bytes {
+		return fieldOptions{}, errors.New("tag options id and bytes are exclusive")
+	}
+
+	return opts, nil
+}
+
+func isPubKeyArray(t reflect.Type) bool {
+	return t.Kind() == reflect.Array && t.Len() == 32 && t.Elem().Kind() == reflect.Uint8
+}
+
+// alignOf returns the alignment of a value of type t.
+func alignOf(t reflect.Type, opts fieldOptions) (int, error) {
+	if opts.align > 0 {
+		return opts.align, nil
+	}
+	if opts.asset {
+		return 8, nil
+	}
+	if opts.id && t != identityType && !isPubKeyArray(t) {
+		return 0, errors.Errorf("id option on type %s, expected [32]byte or types.Identity", t)
+	}
+	if opts.bytes {
+		if !isPubKeyArray(t) {
+			return 0, errors.Errorf("bytes option on type %s, expected [32]byte", t)
+		}
+		return 1, nil
+	}
+	if t == identityType || isPubKeyArray(t) {
+		return idAlignment, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16, reflect.Int32, reflect.Uint32,
+		reflect.Int64, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return int(t.Size()), nil
+	case reflect.Array:
+		return alignOf(t.Elem(), fieldOptions{})
+	case reflect.Struct:
+		maxAlign := 1
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			fieldOpts, err := parseTag(field.Tag.Get(tagName))
+			if err != nil {
+				return 0, errors.Wrapf(err, "parsing tag of field %s", field.Name)
+			}
+			if fieldOpts.skip {
+				continue
+			}
+
+			align, err := alignOf(field.Type, fieldOpts)
+			if err != nil {
+				return 0, errors.Wrapf(err, "field %s", field.Name)
+			}
+			if align > maxAlign {
+				maxAlign = align
+			}
+		}
+		return maxAlign, nil
+	}
+
+	return 0, errors.Errorf("unsupported type %s", t)
+}
+
+// Marshal encodes the struct, or pointer to struct, v.
+func Marshal(v interface{}) ([]byte, error) {
+	value := reflect.Indirect(reflect.ValueOf(v))
+	if value.Kind() != reflect.Struct {
+		return nil, errors.Errorf("expected a struct, got %T", v)
+	}
+
+	var e encoder
+	err := e.encode(value, fieldOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	return e.buf.Bytes(), nil
+}
+
+type encoder struct {
+	buf bytes.Buffer
+}
+
+func (e *encoder) pad(align int) {
+	for e.buf.Len()%align != 0 {
+		e.buf.WriteByte(0)
+	}
+}
+
+func (e *encoder) encode(v reflect.Value, opts fieldOptions) error {
+	align, err := alignOf(v.Type(), opts)
+	if err != nil {
+		return err
+	}
+	e.pad(align)
+
+	switch {
+	case opts.asset:
+		name, err := assetName(v)
+		if err != nil {
+			return err
+		}
+		e.buf.Write(name[:])
+		return nil
+	case v.Type() == identityType:
+		var pubKey [32]byte
+		if id := types.Identity(v.String()); id != "" {
+			pubKey, err = id.ToPubKey(false)
+			if err != nil {
+				return errors.Wrapf(err, "converting identity %s to pubkey", id)
+			}
+		}
+		e.buf.Write(pubKey[:])
+		return nil
+	}
+
+	var scratch [8]byte
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			e.buf.WriteByte(1)
+		} else {
+			e.buf.WriteByte(0)
+		}
+	case reflect.Int8:
+		e.buf.WriteByte(byte(v.Int()))
+	case reflect.Uint8:
+		e.buf.WriteByte(byte(v.Uint()))
+	case reflect.Int16, reflect.Uint16:
+		binary.LittleEndian.PutUint16(scratch[:], uint16(integer(v)))
+		e.buf.Write(scratch[:2])
+	case reflect.Int32, reflect.Uint32:
+		binary.LittleEndian.PutUint32(scratch[:], uint32(integer(v)))
+		e.buf.Write(scratch[:4])
+	case reflect.Int64, reflect.Uint64:
+		binary.LittleEndian.PutUint64(scratch[:], integer(v))
+		e.buf.Write(scratch[:8])
+	case reflect.Float32:
+		binary.LittleEndian.PutUint32(scratch[:], math.Float32bits(float32(v.Float())))
+		e.buf.Write(scratch[:4])
+	case reflect.Float64:
+		binary.LittleEndian.PutUint64(scratch[:], math.Float64bits(v.Float()))
+		e.buf.Write(scratch[:8])
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := e.encode(v.Index(i), fieldOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "element %d", i)
+			}
+		}
+	case reflect.Struct:
+		err := forEachField(v, func(field reflect.Value, name string, opts fieldOptions) error {
+			return e.encode(field, opts)
+		})
+		if err != nil {
+			return err
+		}
+		e.pad(align)
+	default:
+		return errors.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+// Unmarshal decodes data into the struct pointed to by v. Zero public keys decode to an empty types.Identity.
+func Unmarshal(data []byte, v interface{}) error {
+	value := reflect.ValueOf(v)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return errors.Errorf("expected a pointer to a struct, got %T", v)
+	}
+
+	d := decoder{data: data}
+	err := d.decode(value.Elem(), fieldOptions{})
+	if err != nil {
+		return err
+	}
+
+	if d.offset != len(data) {
+		return errors.Errorf("decoded %d bytes out of %d", d.offset, len(data))
+	}
+
+	return nil
+}
+
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) next(size int) ([]byte, error) {
+	if d.offset+size > len(d.data) {
+		return nil, errors.Errorf("need %d bytes at offset %d, only %d available", size, d.offset, len(d.data)-d.offset)
+	}
+
+	b := d.data[d.offset : d.offset+size]
+	d.offset += size
+
+	return b, nil
+}
+
+func (d *decoder) pad(align int) error {
+	padding := (align - d.offset%align) % align
+	_, err := d.next(padding)
+
+	return err
+}
+
+func (d *decoder) decode(v reflect.Value, opts fieldOptions) error {
+	align, err := alignOf(v.Type(), opts)
+	if err != nil {
+		return err
+	}
+	err = d.pad(align)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.asset:
+		b, err := d.next(8)
+		if err != nil {
+			return err
+		}
+		if v.Kind() == reflect.String {
+			v.SetString(string(bytes.TrimRight(b, "\x00")))
+		} else {
+			reflect.Copy(v, reflect.ValueOf(b))
+		}
+		return nil
+	case v.Type() == identityType:
+		b, err := d.next(32)
+		if err != nil {
+			return err
+		}
+		var pubKey [32]byte
+		copy(pubKey[:], b)
+		if pubKey == [32]byte{} {
+			v.SetString("")
+			return nil
+		}
+		var id types.Identity
+		id, err = id.FromPubKey(pubKey, false)
+		if err != nil {
+			return errors.Wrap(err, "converting pubkey to identity")
+		}
+		v.SetString(string(id))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16, reflect.Int32, reflect.Uint32,
+		reflect.Int64, reflect.Uint64, reflect.Float32, reflect.Float64:
+		b, err := d.next(int(v.Type().Size()))
+		if err != nil {
+			return err
+		}
+		setBasic(v, b)
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			err := d.decode(v.Index(i), fieldOptions{})
+			if err != nil {
+				return errors.Wrapf(err, "element %d", i)
+			}
+		}
+	case reflect.Struct:
+		err := forEachField(v, func(field reflect.Value, name string, opts fieldOptions) error {
+			if name == "_" {
+				// padding, read into a throwaway value since blank fields can't be set
+				field = reflect.New(field.Type()).Elem()
+			}
+			return d.decode(field, opts)
+		})
+		if err != nil {
+			return err
+		}
+		return d.pad(align)
+	default:
+		return errors.Errorf("unsupported type %s", v.Type())
+	}
+
+	return nil
+}
+
+func setBasic(v reflect.Value, b []byte) {
+	var raw uint64
+	switch len(b) {
+	case 1:
+		raw = uint64(b[0])
+	case 2:
+		raw = uint64(binary.LittleEndian.Uint16(b))
+	case 4:
+		raw = uint64(binary.LittleEndian.Uint32(b))
+	case 8:
+		raw = binary.LittleEndian.Uint64(b)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(raw != 0)
+	case reflect.Int8:
+		v.SetInt(int64(int8(raw)))
+	case reflect.Int16:
+		v.SetInt(int64(int16(raw)))
+	case reflect.Int32:
+		v.SetInt(int64(int32(raw)))
+	case reflect.Int64:
+		v.SetInt(int64(raw))
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(raw)
+	case reflect.Float32:
+		v.SetFloat(float64(math.Float32frombits(uint32(raw))))
+	case reflect.Float64:
+		v.SetFloat(math.Float64frombits(raw))
+	}
+}
+
+func integer(v reflect.Value) uint64 {
+	switch v.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint64(v.Int())
+	}
+
+	return v.Uint()
+}
+
+func forEachField(v reflect.Value, fn func(field reflect.Value, name string, opts fieldOptions) error) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		opts, err := parseTag(field.Tag.Get(tagName))
+		if err != nil {
+			return errors.Wrapf(err, "parsing tag of field %s", field.Name)
+		}
+		if opts.skip {
+			continue
+		}
+		if field.PkgPath != "" && field.Name != "_" {
+			return errors.Errorf("field %s is not exported", field.Name)
+		}
+
+		err = fn(v.Field(i), field.Name, opts)
+		if err != nil {
+			return errors.Wrapf(err, "field %s", field.Name)
+		}
+	}
+
+	return nil
+}
+
+func assetName(v reflect.Value) ([8]byte, error) {
+	var name [8]byte
+	switch {
+	case v.Kind() == reflect.String:
+		if v.Len() > 7 {
+			return name, errors.Errorf("asset name '%s' is longer than 7", v.String())
+		}
+		copy(name[:], v.String())
+	case v.Kind() == reflect.Array && v.Len() == 8 && v.Type().Elem().Kind() == reflect.Uint8:
+		reflect.Copy(reflect.ValueOf(name[:]), v)
+	default:
+		return name, errors.Errorf("asset name must be a string or [8]byte, got %s", v.Type())
+	}
+
+	return name, nil
+}
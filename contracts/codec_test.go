@@ -0,0 +1,176 @@
+package contracts
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIdentity = "UIJLDDELETUYEHFKZPQGVOOOTLHCNQWAZAXHLSXWMEDLRQEWKNSJVZIGFPBD"
+
+type testOrder struct {
+	Entity         types.Identity
+	Price          int64
+	NumberOfShares int64
+}
+
+type testInput struct {
+	Flag      bool
+	Issuer    types.Identity
+	AssetName string `qubic:"asset"`
+	Small     int16
+	Value     uint32
+	Raw       [3]byte
+	Decimals  int8
+	Orders    [2]testOrder
+	Ignored   string `qubic:"-"`
+	Key       [32]byte
+	_         [4]byte
+	Last      int8
+}
+
+func TestMarshal(t *testing.T) {
+	identity := types.Identity(testIdentity)
+	pubKey, err := identity.ToPubKey(false)
+	require.NoError(t, err)
+
+	input := testInput{
+		Flag:      true,
+		Issuer:    testIdentity,
+		AssetName: "CFB",
+		Small:     -2,
+		Value:     7,
+		Raw:       [3]byte{1, 2, 3},
+		Decimals:  -1,
+		Orders:    [2]testOrder{{Entity: testIdentity, Price: 5, NumberOfShares: 6}},
+		Ignored:   "not encoded",
+		Key:       [32]byte{9},
+		Last:      4,
+	}
+
+	data, err := Marshal(&input)
+	require.NoError(t, err)
+
+	expected := make([]byte, 0, 200)
+	expected = append(expected, 1, 0, 0, 0, 0, 0, 0, 0) // flag, padded to the id alignment
+	expected = append(expected, pubKey[:]...)
+	expected = append(expected, 'C', 'F', 'B', 0, 0, 0, 0, 0)
+	expected = append(expected, 0xFE, 0xFF, 0, 0)          // small, padded to the uint32 alignment
+	expected = append(expected, 7, 0, 0, 0)                // value
+	expected = append(expected, 1, 2, 3, 0xFF, 0, 0, 0, 0) // raw, decimals, padded to the order alignment
+	expected = append(expected, pubKey[:]...)              // first order
+	expected = append(expected, 5, 0, 0, 0, 0, 0, 0, 0, 6, 0, 0, 0, 0, 0, 0, 0)
+	expected = append(expected, make([]byte, 48)...)                       // second order
+	expected = append(expected, append([]byte{9}, make([]byte, 31)...)...) // key
+	expected = append(expected, 0, 0, 0, 0, 4, 0, 0, 0)                    // padding, last, padded to the struct alignment
+	assert.Equal(t, expected, data)
+
+	var decoded testInput
+	require.NoError(t, Unmarshal(data, &decoded))
+	input.Ignored = ""
+	input.Orders[1].Entity = ""
+	assert.Equal(t, input, decoded)
+}
+
+func TestMarshal_Errors(t *testing.T) {
+	_, err := Marshal(struct {
+		AssetName string `qubic:"asset"`
+	}{AssetName: "TOOLONGNAME"})
+	require.Error(t, err)
+
+	_, err = Marshal(struct{ Name string }{Name: "no tag"})
+	require.Error(t, err)
+
+	_, err = Marshal(struct {
+		Key uint64 `qubic:"id"`
+	}{})
+	require.Error(t, err)
+
+	_, err = Marshal(struct{ value int64 }{})
+	require.Error(t, err)
+
+	_, err = Marshal(struct {
+		Key uint64 `qubic:"bytes"`
+	}{})
+	require.Error(t, err)
+
+	_, err = Marshal(struct {
+		Key [32]byte `qubic:"id,bytes"`
+	}{})
+	require.Error(t, err)
+}
+
+// respondedEntity mirrors the RespondedEntity struct of the core, whose m256i siblings follow two uint32 at offset 72.
+type respondedEntity struct {
+	PublicKey                  [32]byte
+	IncomingAmount             int64
+	OutgoingAmount             int64
+	NumberOfIncomingTransfers  uint32
+	NumberOfOutgoingTransfers  uint32
+	LatestIncomingTransferTick uint32
+	LatestOutgoingTransferTick uint32
+	Tick                       uint32
+	SpectrumIndex              int32
+	Siblings                   [types.SpectrumDepth][32]byte
+}
+
+func TestMarshal_IdAlignment(t *testing.T) {
+	entity := respondedEntity{PublicKey: [32]byte{1}, IncomingAmount: 2, Tick: 3, SpectrumIndex: 4}
+	entity.Siblings[0] = [32]byte{5}
+
+	// types.AddressInfo is read from the nodes as is, without padding
+	var buff bytes.Buffer
+	require.NoError(t, binary.Write(&buff, binary.LittleEndian, types.AddressInfo{
+		AddressData:   types.AddressData{PublicKey: entity.PublicKey, IncomingAmount: 2},
+		Tick:          3,
+		SpectrumIndex: 4,
+		Siblings:      entity.Siblings,
+	}))
+
+	data, err := Marshal(entity)
+	require.NoError(t, err)
+	assert.Equal(t, buff.Bytes(), data)
+
+	// an id after a single byte starts at the next multiple of 8, as a m256i does
+	withFlag := struct {
+		Flag   uint8
+		Issuer [32]byte
+	}{Flag: 1, Issuer: [32]byte{2}}
+	data, err = Marshal(withFlag)
+	require.NoError(t, err)
+	require.Len(t, data, 40)
+	assert.Equal(t, []byte{1, 0, 0, 0, 0, 0, 0, 0, 2}, data[:9])
+
+	// plain bytes are not aligned
+	withBytes := struct {
+		Flag   uint8
+		Digest [32]byte `qubic:"bytes"`
+	}{Flag: 1, Digest: [32]byte{2}}
+	data, err = Marshal(withBytes)
+	require.NoError(t, err)
+	require.Len(t, data, 33)
+	assert.Equal(t, []byte{1, 2}, data[:2])
+
+	var decoded struct {
+		Flag   uint8
+		Digest [32]byte `qubic:"bytes"`
+	}
+	require.NoError(t, Unmarshal(data, &decoded))
+	assert.Equal(t, withBytes, decoded)
+}
+
+func TestUnmarshal_Size(t *testing.T) {
+	var out struct {
+		A int32
+		B int64
+	}
+
+	require.Error(t, Unmarshal(make([]byte, 15), &out))
+	require.Error(t, Unmarshal(make([]byte, 17), &out))
+	require.NoError(t, Unmarshal(make([]byte, 16), &out))
+	require.Error(t, Unmarshal(make([]byte, 16), out))
+}
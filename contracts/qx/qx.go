@@ -0,0 +1,218 @@
+// Package qx calls the functions of the QX asset exchange contract and decodes their outputs. The transactions calling
+// its procedures are built with the types.NewQx* functions.
+package qx
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	qubic "github.com/qubic/go-node-connector"
+	"github.com/qubic/go-node-connector/contracts"
+	"github.com/qubic/go-node-connector/types"
+)
+
+const ContractIndex = types.QxContractIndex
+
+const (
+	feesFunction            = 1
+	assetAskOrdersFunction  = 2
+	assetBidOrdersFunction  = 3
+	entityAskOrdersFunction = 4
+	entityBidOrdersFunction = 5
+)
+
+// maxOrders is the number of orders returned by one order book call, empty slots are zero.
+const maxOrders = 256
+
+// Querier runs contract functions, *qubic.Client implements it.
+type Querier interface {
+	QuerySmartContract(ctx context.Context, rcf qubic.RequestContractFunction, requestData []byte) (types.SmartContractData, error)
+}
+
+type Fees struct {
+	AssetIssuanceFee uint32
+	TransferFee      uint32
+	// TradeFee is in billionths of the traded amount.
+	TradeFee uint32
+}
+
+// AssetOrder is an order of the order book of an asset.
+type AssetOrder struct {
+	Entity         types.Identity
+	Price          int64
+	NumberOfShares int64
+}
+
+// EntityOrder is an order of the order book of an entity.
+type EntityOrder struct {
+	Issuer         types.Identity
+	AssetName      string
+	Price          int64
+	NumberOfShares int64
+}
+
+type Client struct {
+	querier Querier
+}
+
+func NewClient(querier Querier) *Client {
+	return &Client{querier: querier}
+}
+
+func (c *Client) Fees(ctx context.Context) (Fees, error) {
+	var fees Fees
+	err := c.call(ctx, feesFunction, nil, &fees)
+	if err != nil {
+		return Fees{}, errors.Wrap(err, "calling fees")
+	}
+
+	return fees, nil
+}
+
+// AssetAskOrders returns the ask orders of an asset, cheapest first, starting at offset.
+func (c *Client) AssetAskOrders(ctx context.Context, issuer, assetName string, offset uint64) ([]AssetOrder, error) {
+	orders, err := c.assetOrders(ctx, assetAskOrdersFunction, issuer, assetName, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "calling asset ask orders")
+	}
+
+	return orders, nil
+}
+
+// AssetBidOrders returns the bid orders of an asset, highest first, starting at offset.
+func (c *Client) AssetBidOrders(ctx context.Context, issuer, assetName string, offset uint64) ([]AssetOrder, error) {
+	orders, err := c.assetOrders(ctx, assetBidOrdersFunction, issuer, assetName, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "calling asset bid orders")
+	}
+
+	return orders, nil
+}
+
+// EntityAskOrders returns the ask orders of an entity, starting at offset.
+func (c *Client) EntityAskOrders(ctx context.Context, entity string, offset uint64) ([]EntityOrder, error) {
+	orders, err := c.entityOrders(ctx, entityAskOrdersFunction, entity, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "calling entity ask orders")
+	}
+
+	return orders, nil
+}
+
+// EntityBidOrders returns the bid orders of an entity, starting at offset.
+func (c *Client) EntityBidOrders(ctx context.Context, entity string, offset uint64) ([]EntityOrder, error) {
+	orders, err := c.entityOrders(ctx, entityBidOrdersFunction, entity, offset)
+	if err != nil {
+		return nil, errors.Wrap(err, "calling entity bid orders")
+	}
+
+	return orders, nil
+}
+
+type assetOrdersInput struct {
+	Issuer    types.Identity
+	AssetName string `qubic:"asset"`
+	Offset    uint64
+}
+
+type assetOrdersOutput struct {
+	Orders [maxOrders]struct {
+		Entity         types.Identity
+		Price          int64
+		NumberOfShares int64
+	}
+}
+
+func (c *Client) assetOrders(ctx context.Context, function uint16, issuer, assetName string, offset uint64) ([]AssetOrder, error) {
+	var output assetOrdersOutput
+	err := c.call(ctx, function, assetOrdersInput{Issuer: types.Identity(issuer), AssetName: assetName, Offset: offset}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []AssetOrder
+	for _, order := range output.Orders {
+		if order.Entity == "" {
+			continue
+		}
+		orders = append(orders, AssetOrder{Entity: order.Entity, Price: order.Price, NumberOfShares: order.NumberOfShares})
+	}
+
+	return orders, nil
+}
+
+type entityOrdersInput struct {
+	Entity types.Identity
+	Offset uint64
+}
+
+type entityOrdersOutput struct {
+	Orders [maxOrders]struct {
+		// Issuer is a [32]byte since assets issued by the protocol, like QX shares, have a zero issuer
+		Issuer         [32]byte
+		AssetName      string `qubic:"asset"`
+		Price          int64
+		NumberOfShares int64
+	}
+}
+
+func (c *Client) entityOrders(ctx context.Context, function uint16, entity string, offset uint64) ([]EntityOrder, error) {
+	var output entityOrdersOutput
+	err := c.call(ctx, function, entityOrdersInput{Entity: types.Identity(entity), Offset: offset}, &output)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []EntityOrder
+	for _, order := range output.Orders {
+		// the asset name marks the slot as used, the issuer can be zero
+		if order.AssetName == "" {
+			continue
+		}
+
+		var issuer types.Identity
+		issuer, err := issuer.FromPubKey(order.Issuer, false)
+		if err != nil {
+			return nil, errors.Wrap(err, "getting issuer identity")
+		}
+
+		orders = append(orders, EntityOrder{
+			Issuer:         issuer,
+			AssetName:      order.AssetName,
+			Price:          order.Price,
+			NumberOfShares: order.NumberOfShares,
+		})
+	}
+
+	return orders, nil
+}
+
+// call runs a contract function, input and output are encoded with contracts.Marshal and contracts.Unmarshal.
+func (c *Client) call(ctx context.Context, function uint16, input interface{}, output interface{}) error {
+	var requestData []byte
+	if input != nil {
+		var err error
+		requestData, err = contracts.Marshal(input)
+		if err != nil {
+			return errors.Wrap(err, "marshalling input")
+		}
+	}
+
+	rcf := qubic.RequestContractFunction{
+		ContractIndex: ContractIndex,
+		InputType:     function,
+		InputSize:     uint16(len(requestData)),
+	}
+
+	data, err := c.querier.QuerySmartContract(ctx, rcf, requestData)
+	if err != nil {
+		return errors.Wrap(err, "querying smart contract")
+	}
+
+	err = contracts.Unmarshal(data.Data, output)
+	if err != nil {
+		return errors.Wrap(err, "unmarshalling output")
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package qx
+
+import (
+	"context"
+	"testing"
+
+	qubic "github.com/qubic/go-node-connector"
+	"github.com/qubic/go-node-connector/contracts"
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	testIssuer = "CFBMEMZOIDEXQAUXYYSZIURADQLAPWPMNJXQSNVQZAHYVOPYUKKJBJUCTVJL"
+	testEntity = "UIJLDDELETUYEHFKZPQGVOOOTLHCNQWAZAXHLSXWMEDLRQEWKNSJVZIGFPBD"
+)
+
+func newTestQxClient(t *testing.T) (*qubictest.Server, *Client) {
+	server, err := qubictest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	client, err := qubic.NewClient(context.Background(), server.Host(), server.Port())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return server, NewClient(client)
+}
+
+func pubKey(t *testing.T, id string) [32]byte {
+	identity := types.Identity(id)
+	key, err := identity.ToPubKey(false)
+	require.NoError(t, err)
+
+	return key
+}
+
+func encode(t *testing.T, data interface{}) []byte {
+	encoded, err := contracts.Marshal(data)
+	require.NoError(t, err)
+
+	return encoded
+}
+
+func TestClient_Fees(t *testing.T) {
+	server, client := newTestQxClient(t)
+	server.SetContractFunction(ContractIndex, feesFunction, func(input []byte) ([]byte, error) {
+		return encode(t, Fees{AssetIssuanceFee: 1000000000, TransferFee: 100, TradeFee: 3000000}), nil
+	})
+
+	fees, err := client.Fees(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, Fees{AssetIssuanceFee: 1000000000, TransferFee: 100, TradeFee: 3000000}, fees)
+}
+
+func TestClient_AssetAskOrders(t *testing.T) {
+	server, client := newTestQxClient(t)
+
+	var gotInput assetOrdersInput
+	server.SetContractFunction(ContractIndex, assetAskOrdersFunction, func(input []byte) ([]byte, error) {
+		require.NoError(t, contracts.Unmarshal(input, &gotInput))
+
+		var output assetOrdersOutput
+		output.Orders[0].Entity = testEntity
+		output.Orders[0].Price = 5
+		output.Orders[0].NumberOfShares = 10
+		return encode(t, output), nil
+	})
+
+	orders, err := client.AssetAskOrders(context.Background(), testIssuer, "CFB", 3)
+	require.NoError(t, err)
+	assert.Equal(t, []AssetOrder{{Entity: testEntity, Price: 5, NumberOfShares: 10}}, orders)
+	assert.Equal(t, assetOrdersInput{Issuer: testIssuer, AssetName: "CFB", Offset: 3}, gotInput)
+
+	_, err = client.AssetBidOrders(context.Background(), testIssuer, "CFB", 0)
+	require.Error(t, err, "no output")
+}
+
+func TestClient_EntityBidOrders(t *testing.T) {
+	server, client := newTestQxClient(t)
+
+	server.SetContractFunction(ContractIndex, entityBidOrdersFunction, func(input []byte) ([]byte, error) {
+		var output entityOrdersOutput
+		output.Orders[0].Issuer = pubKey(t, testIssuer)
+		output.Orders[0].AssetName = "CFB"
+		output.Orders[0].Price = 2
+		output.Orders[0].NumberOfShares = 7
+		output.Orders[1].AssetName = "QX"
+		output.Orders[1].Price = 1
+		output.Orders[1].NumberOfShares = 1
+		return encode(t, output), nil
+	})
+
+	orders, err := client.EntityBidOrders(context.Background(), testEntity, 0)
+	require.NoError(t, err)
+	require.Len(t, orders, 2)
+	assert.Equal(t, EntityOrder{Issuer: testIssuer, AssetName: "CFB", Price: 2, NumberOfShares: 7}, orders[0])
+	assert.Equal(t, "QX", orders[1].AssetName)
+}
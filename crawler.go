@@ -0,0 +1,192 @@
+package qubic
+
+import (
+	"context"
+	"net"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultNodePort           = "21841"
+	defaultCrawlerConcurrency = 8
+)
+
+type CrawlerConfig struct {
+	// Seeds are the nodes the crawl starts from, optionally with a port.
+	Seeds []string
+	// NodePort is the port of the advertised peers and of the seeds without one, 21841 when empty.
+	NodePort string
+	// Concurrency is the number of nodes visited at the same time, 8 when zero.
+	Concurrency int
+	// Timeout bounds connecting to a node and querying it, 5 seconds when zero.
+	Timeout time.Duration
+	// MaxNodes stops following peers once that many nodes are known. Zero means no limit.
+	MaxNodes int
+}
+
+// PeerNode is what the crawler learned about a node.
+type PeerNode struct {
+	// Address is the host:port of the node.
+	Address   string `json:"address"`
+	Reachable bool   `json:"reachable"`
+	// Error is why the node is not reachable.
+	Error   string `json:"error,omitempty"`
+	Version int16  `json:"version"`
+	Epoch   uint16 `json:"epoch"`
+	Tick    uint32 `json:"tick"`
+	// Lag is the number of ticks the node is behind the highest tick seen during the crawl.
+	Lag uint32 `json:"lag"`
+	// Peers are the addresses the node advertised in the same host:port form as Address, the edges of the graph.
+	Peers []string `json:"peers"`
+}
+
+// PeerGraph is the result of a crawl, ready to be exported as JSON.
+type PeerGraph struct {
+	CrawledAt time.Time  `json:"crawled_at"`
+	MaxTick   uint32     `json:"max_tick"`
+	Nodes     []PeerNode `json:"nodes"`
+}
+
+// ReachableNodes returns the addresses of the reachable nodes lagging at most maxLag ticks, e.g. to feed a
+// StaticNodeSource.
+func (g *PeerGraph) ReachableNodes(maxLag uint32) []string {
+	var nodes []string
+	for _, node := range g.Nodes {
+		if node.Reachable && node.Lag <= maxLag {
+			nodes = append(nodes, node.Address)
+		}
+	}
+
+	return nodes
+}
+
+// Crawler discovers the network by following the peers advertised by every node in its ExchangePublicPeers packet.
+type Crawler struct {
+	config CrawlerConfig
+}
+
+func NewCrawler(config CrawlerConfig) *Crawler {
+	if config.NodePort == "" {
+		config.NodePort = defaultNodePort
+	}
+	if config.Concurrency <= 0 {
+		config.Concurrency = defaultCrawlerConcurrency
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaultTimeout
+	}
+
+	return &Crawler{config: config}
+}
+
+// Crawl visits the seeds and then, breadth first, every peer they lead to. Unreachable nodes are part of the graph.
+// When ctx is done the crawl stops and returns the graph of the nodes visited so far along with the context error.
+func (c *Crawler) Crawl(ctx context.Context) (*PeerGraph, error) {
+	if len(c.config.Seeds) == 0 {
+		return nil, errors.New("no seed nodes")
+	}
+
+	graph := PeerGraph{CrawledAt: time.Now()}
+
+	known := make(map[string]bool)
+	var queue []string
+	for _, seed := range c.config.Seeds {
+		seed = normalizeNodeAddress(seed, c.config.NodePort)
+		if !known[seed] {
+			known[seed] = true
+			queue = append(queue, seed)
+		}
+	}
+
+	for len(queue) > 0 && ctx.Err() == nil {
+		visited := c.visitAll(ctx, queue)
+		queue = nil
+
+		for _, node := range visited {
+			graph.Nodes = append(graph.Nodes, node)
+
+			for _, peer := range node.Peers {
+				if known[peer] || (c.config.MaxNodes > 0 && len(known) >= c.config.MaxNodes) {
+					continue
+				}
+				known[peer] = true
+				queue = append(queue, peer)
+			}
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		if node.Reachable && node.Tick > graph.MaxTick {
+			graph.MaxTick = node.Tick
+		}
+	}
+	for i := range graph.Nodes {
+		if graph.Nodes[i].Reachable {
+			graph.Nodes[i].Lag = graph.MaxTick - graph.Nodes[i].Tick
+		}
+	}
+
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].Address < graph.Nodes[j].Address })
+
+	return &graph, ctx.Err()
+}
+
+// normalizeNodeAddress gives every node a single host:port form, so that a seed with a port and the same node
+// advertised without one are only crawled once.
+func normalizeNodeAddress(address, defaultPort string) string {
+	return net.JoinHostPort(splitNodeAddress(address, defaultPort))
+}
+
+func (c *Crawler) visitAll(ctx context.Context, addresses []string) []PeerNode {
+	nodes := make([]PeerNode, len(addresses))
+	sem := make(chan struct{}, c.config.Concurrency)
+
+	var wg sync.WaitGroup
+	for i, address := range addresses {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, address string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			nodes[i] = c.visit(ctx, address)
+		}(i, address)
+	}
+	wg.Wait()
+
+	return nodes
+}
+
+func (c *Crawler) visit(ctx context.Context, address string) PeerNode {
+	node := PeerNode{Address: address}
+
+	ctx, cancel := context.WithTimeout(ctx, c.config.Timeout)
+	defer cancel()
+
+	host, port := splitNodeAddress(address, c.config.NodePort)
+	client, err := NewClient(ctx, host, port)
+	if err != nil {
+		node.Error = errors.Wrap(err, "connecting").Error()
+		return node
+	}
+	defer client.Close()
+
+	systemInfo, err := client.GetSystemInfo(ctx)
+	if err != nil {
+		node.Error = errors.Wrap(err, "getting system info").Error()
+		return node
+	}
+
+	node.Reachable = true
+	node.Version = systemInfo.Version
+	node.Epoch = systemInfo.Epoch
+	node.Tick = systemInfo.Tick
+	for _, peer := range client.Peers {
+		node.Peers = append(node.Peers, normalizeNodeAddress(peer, c.config.NodePort))
+	}
+
+	return node
+}
@@ -0,0 +1,82 @@
+package qubic
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrawler_Crawl(t *testing.T) {
+	seed, err := qubictest.NewServerAt("127.0.0.1:0")
+	require.NoError(t, err)
+	defer seed.Close()
+	port := seed.Port()
+
+	// nodes only advertise IPs, so the other nodes share the port of the seed on other loopback IPs
+	peer, err := qubictest.NewServerAt(net.JoinHostPort("127.0.0.2", port))
+	if err != nil {
+		t.Skipf("loopback alias not available: %s", err)
+	}
+	defer peer.Close()
+
+	require.NoError(t, seed.SetPeers([]string{"127.0.0.2", "127.0.0.3"}))
+	require.NoError(t, peer.SetPeers([]string{"127.0.0.1"}))
+	seed.SetSystemInfo(types.SystemInfo{Version: 231, Epoch: 150, Tick: 1000})
+	peer.SetSystemInfo(types.SystemInfo{Version: 230, Epoch: 150, Tick: 990})
+
+	crawler := NewCrawler(CrawlerConfig{Seeds: []string{"127.0.0.1"}, NodePort: port})
+	graph, err := crawler.Crawl(context.Background())
+	require.NoError(t, err)
+
+	require.Len(t, graph.Nodes, 3)
+	assert.Equal(t, uint32(1000), graph.MaxTick)
+
+	addr := func(ip string) string { return net.JoinHostPort(ip, port) }
+	assert.Equal(t, PeerNode{Address: addr("127.0.0.1"), Reachable: true, Version: 231, Epoch: 150, Tick: 1000, Peers: []string{addr("127.0.0.2"), addr("127.0.0.3")}}, graph.Nodes[0])
+	assert.Equal(t, PeerNode{Address: addr("127.0.0.2"), Reachable: true, Version: 230, Epoch: 150, Tick: 990, Lag: 10, Peers: []string{addr("127.0.0.1")}}, graph.Nodes[1])
+	assert.False(t, graph.Nodes[2].Reachable)
+	assert.NotEmpty(t, graph.Nodes[2].Error)
+
+	assert.Equal(t, []string{addr("127.0.0.1")}, graph.ReachableNodes(5))
+	assert.Equal(t, []string{addr("127.0.0.1"), addr("127.0.0.2")}, graph.ReachableNodes(10))
+
+	exported, err := json.Marshal(graph)
+	require.NoError(t, err)
+	var imported PeerGraph
+	require.NoError(t, json.Unmarshal(exported, &imported))
+	assert.Equal(t, graph.Nodes, imported.Nodes)
+}
+
+func TestCrawler_MaxNodes(t *testing.T) {
+	seed, err := qubictest.NewServer()
+	require.NoError(t, err)
+	defer seed.Close()
+	require.NoError(t, seed.SetPeers([]string{"127.0.0.2", "127.0.0.3"}))
+
+	crawler := NewCrawler(CrawlerConfig{Seeds: []string{seed.Addr()}, MaxNodes: 2})
+	graph, err := crawler.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 2)
+	assert.True(t, graph.Nodes[0].Reachable)
+}
+
+func TestCrawler_NormalizesAddresses(t *testing.T) {
+	seed, err := qubictest.NewServer()
+	require.NoError(t, err)
+	defer seed.Close()
+	// the seed advertises itself without a port
+	require.NoError(t, seed.SetPeers([]string{"127.0.0.1"}))
+
+	crawler := NewCrawler(CrawlerConfig{Seeds: []string{seed.Addr(), "127.0.0.1"}, NodePort: seed.Port()})
+	graph, err := crawler.Crawl(context.Background())
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 1)
+	assert.Equal(t, seed.Addr(), graph.Nodes[0].Address)
+	assert.Equal(t, []string{seed.Addr()}, graph.Nodes[0].Peers)
+}
@@ -0,0 +1,42 @@
+package qubic
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrNoHealthyNode is returned by the Pool when every candidate node failed or is unhealthy.
+	ErrNoHealthyNode = errors.New("no healthy node")
+	// ErrClientClosed is returned by the requests of a Client that was closed.
+	ErrClientClosed = errors.New("client closed")
+	// ErrTimeout is returned when a node does not answer before the context deadline, or defaultTimeout without one.
+	ErrTimeout = errors.New("timeout waiting for response")
+	// ErrTxNotFound is returned by GetTransactionByID when the transaction is not part of the tick.
+	ErrTxNotFound = errors.New("transaction not found")
+)
+
+// ErrNodeUnreachable is returned when the connection to a node can't be established, Err is the dial error.
+type ErrNodeUnreachable struct {
+	Addr string
+	Err  error
+}
+
+func (e ErrNodeUnreachable) Error() string {
+	return fmt.Sprintf("node %s unreachable: %s", e.Addr, e.Err)
+}
+
+func (e ErrNodeUnreachable) Unwrap() error {
+	return e.Err
+}
+
+// ErrTickInFuture is returned when a tick after the current tick of the node is requested.
+type ErrTickInFuture struct {
+	Requested uint32
+	Latest    uint32
+}
+
+func (e ErrTickInFuture) Error() string {
+	return fmt.Sprintf("requested tick %d is in the future, latest tick is %d", e.Requested, e.Latest)
+}
@@ -0,0 +1,50 @@
+package qubic
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_ErrTickInFuture(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 1000})
+
+	_, err := client.GetTickData(context.Background(), 1001)
+	wrapped := errors.Wrap(err, "fetching tick")
+
+	var tickErr ErrTickInFuture
+	require.True(t, errors.As(wrapped, &tickErr))
+	assert.Equal(t, ErrTickInFuture{Requested: 1001, Latest: 1000}, tickErr)
+}
+
+func TestNewClient_ErrNodeUnreachable(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, listener.Close())
+
+	_, err = NewClient(context.Background(), host, port)
+	wrapped := errors.Wrap(err, "connecting")
+
+	var unreachable ErrNodeUnreachable
+	require.True(t, errors.As(wrapped, &unreachable))
+	assert.Equal(t, listener.Addr().String(), unreachable.Addr)
+
+	var opErr *net.OpError
+	assert.True(t, errors.As(wrapped, &opErr))
+}
+
+func TestClient_ErrClientClosed(t *testing.T) {
+	_, client := newFakeNodeClient(t)
+	require.NoError(t, client.Close())
+
+	_, err := client.GetTickInfo(context.Background())
+	assert.True(t, errors.Is(err, ErrClientClosed))
+}
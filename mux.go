@@ -0,0 +1,227 @@
+package qubic
+
+import (
+	"context"
+	"math/rand"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// maxQueuedBytes bounds the packets buffered for one pending request and not yet consumed by its caller.
+const maxQueuedBytes = 16 * types.MaxPacketSize
+
+// packetQueue buffers the packets routed to one pending request. Pushing never blocks, so a slow caller cannot stall
+// the connection reader. The buffer is bounded by maxBytes, a request whose packets overflow it fails.
+type packetQueue struct {
+	mu       sync.Mutex
+	packets  [][]byte
+	size     int
+	maxBytes int
+	err      error
+	notify   chan struct{}
+}
+
+func newPacketQueue() *packetQueue {
+	return &packetQueue{maxBytes: maxQueuedBytes, notify: make(chan struct{}, 1)}
+}
+
+// push buffers the packet, or drops it once the queue failed. Overflowing the queue fails it and drops the packets
+// buffered so far.
+func (q *packetQueue) push(packet []byte) {
+	q.mu.Lock()
+	switch {
+	case q.err != nil:
+	case q.size+len(packet) > q.maxBytes:
+		q.err = errors.Wrapf(types.ErrResponseTooLarge, "more than %d bytes buffered", q.maxBytes)
+		q.packets = nil
+		q.size = 0
+	default:
+		q.packets = append(q.packets, packet)
+		q.size += len(packet)
+	}
+	q.mu.Unlock()
+
+	q.wake()
+}
+
+// fail makes every later pop return err once the buffered packets are consumed.
+func (q *packetQueue) fail(err error) {
+	q.mu.Lock()
+	if q.err == nil {
+		q.err = err
+	}
+	q.mu.Unlock()
+
+	q.wake()
+}
+
+func (q *packetQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+func (q *packetQueue) pop(ctx context.Context, deadline time.Time) ([]byte, error) {
+	timer := time.NewTimer(time.Until(deadline))
+	defer timer.Stop()
+
+	for {
+		q.mu.Lock()
+		if len(q.packets) > 0 {
+			packet := q.packets[0]
+			q.packets = q.packets[1:]
+			q.size -= len(packet)
+			q.mu.Unlock()
+			return packet, nil
+		}
+		err := q.err
+		q.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+
+		select {
+		case <-q.notify:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-timer.C:
+			return nil, ErrTimeout
+		}
+	}
+}
+
+// packetStreamReader presents the packets routed to a request as one continuous stream, which is what the
+// ReaderUnmarshaler implementations expect. A response spanning more than maxBytes fails, the queue alone doesn't
+// bound it since the decoder keeps draining it.
+type packetStreamReader struct {
+	ctx      context.Context
+	deadline time.Time
+	queue    *packetQueue
+	buf      []byte
+	read     int
+	maxBytes int
+}
+
+func (r *packetStreamReader) Read(p []byte) (int, error) {
+	if len(r.buf) == 0 {
+		packet, err := r.queue.pop(r.ctx, r.deadline)
+		if err != nil {
+			return 0, err
+		}
+		if r.read+len(packet) > r.maxBytes {
+			return 0, errors.Wrapf(types.ErrResponseTooLarge, "more than %d bytes read", r.maxBytes)
+		}
+		r.read += len(packet)
+		r.buf = packet
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
+
+// readLoop reads whole packets from the connection and routes them to the pending request with the same dejaVu.
+// Packets that don't belong to any request are unsolicited and handled separately.
+func (qc *Client) readLoop() {
+	defer close(qc.readerDone)
+
+	for {
+		header, packet, err := readPacket(qc.conn)
+		if err != nil {
+			qc.failPending(err)
+			return
+		}
+
+		qc.mu.Lock()
+		queue, ok := qc.pending[header.DejaVu]
+		qc.mu.Unlock()
+
+		if ok && header.DejaVu != 0 {
+			queue.push(packet)
+			continue
+		}
+
+		qc.handleUnsolicited(header, packet)
+	}
+}
+
+func (qc *Client) handleUnsolicited(header types.RequestResponseHeader, packet []byte) {
+	qc.publish(header, packet)
+
+	if header.Type != types.ExchangePublicPeers {
+		return
+	}
+
+	// only the peers sent on connect are kept, later exchanges are dropped
+	select {
+	case qc.peersPacket <- packet:
+	default:
+	}
+}
+
+// registerRequest reserves a dejaVu that is not used by any pending request.
+func (qc *Client) registerRequest() (uint32, *packetQueue, error) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if qc.readErr != nil {
+		return 0, nil, errors.Wrap(qc.readErr, "connection is not readable")
+	}
+
+	for {
+		dejaVu := uint32(rand.Int31())
+		if _, ok := qc.pending[dejaVu]; dejaVu == 0 || ok {
+			continue
+		}
+
+		queue := newPacketQueue()
+		qc.pending[dejaVu] = queue
+		return dejaVu, queue, nil
+	}
+}
+
+// broken tells whether the connection can't serve requests anymore.
+func (qc *Client) broken() bool {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	return qc.closed || qc.readErr != nil
+}
+
+func (qc *Client) unregisterRequest(dejaVu uint32) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	delete(qc.pending, dejaVu)
+}
+
+func (qc *Client) failPending(err error) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if qc.closed {
+		err = ErrClientClosed
+	}
+	qc.readErr = err
+
+	for _, queue := range qc.pending {
+		queue.fail(err)
+	}
+}
+
+// readPacket reads one whole packet and returns its header and raw bytes, header included.
+func readPacket(conn net.Conn) (types.RequestResponseHeader, []byte, error) {
+	packet, err := types.ReadPacket(conn, types.MaxPacketSize)
+	if err != nil {
+		return types.RequestResponseHeader{}, nil, err
+	}
+
+	return packet.Header, packet.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+package qubic
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFakeNodeClient(t *testing.T) (*qubictest.Server, *Client) {
+	server, err := qubictest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	client, err := NewClient(context.Background(), server.Host(), server.Port())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return server, client
+}
+
+func TestClient_ConcurrentRequests(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	tickInfo := types.TickInfo{Epoch: 150, Tick: 1000}
+	server.SetTickInfo(tickInfo)
+
+	for tick := uint32(900); tick < 910; tick++ {
+		tickData := types.TickData{Epoch: 150, Tick: tick}
+		var txs types.Transactions
+		for i := 0; i < int(tick%5)+1; i++ {
+			tx := types.Transaction{Tick: tick, Amount: int64(i), Input: []byte{}, Signature: [64]byte{byte(i + 1)}}
+			digest, err := tx.Digest()
+			require.NoError(t, err)
+			tickData.TransactionDigests[i] = digest
+			txs = append(txs, tx)
+		}
+		server.SetTickData(tickData)
+		server.SetTickTransactions(tick, txs)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(tick uint32) {
+			defer wg.Done()
+
+			txs, err := client.GetTickTransactions(context.Background(), tick)
+			if assert.NoError(t, err) {
+				assert.Len(t, txs, int(tick%5)+1)
+				for _, tx := range txs {
+					assert.Equal(t, tick, tx.Tick)
+				}
+			}
+
+			got, err := client.GetTickInfo(context.Background())
+			if assert.NoError(t, err) {
+				assert.Equal(t, tickInfo, got)
+			}
+		}(900 + uint32(i%10))
+	}
+
+	// unsolicited broadcasts in between responses must not reach any request
+	for i := 0; i < 10; i++ {
+		require.NoError(t, server.Push(types.BroadcastTransaction, make([]byte, 144)))
+		require.NoError(t, server.Push(types.ExchangePublicPeers, make([]byte, 16)))
+	}
+
+	wg.Wait()
+}
+
+func TestClient_UnansweredRequest(t *testing.T) {
+	_, client := newFakeNodeClient(t)
+
+	// the fake node ignores unknown request types
+	const unknownRequestType = 250
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	var packet types.Packet
+	err := client.Do(ctx, unknownRequestType, nil, &packet)
+	assert.Error(t, err)
+
+	errs := make(chan error, 1)
+	go func() {
+		var packet types.Packet
+		errs <- client.Do(context.Background(), unknownRequestType, nil, &packet)
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(t, client.Close())
+
+	select {
+	case err := <-errs:
+		assert.Error(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("pending request was not failed when the client was closed")
+	}
+
+	_, err = client.GetTickInfo(context.Background())
+	assert.Error(t, err)
+}
+
+func TestClient_Do(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	tickInfo := types.TickInfo{Epoch: 150, Tick: 1000}
+	server.SetTickInfo(tickInfo)
+
+	var packet types.Packet
+	err := client.Do(context.Background(), types.CurrentTickInfoRequest, nil, &packet)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(types.CurrentTickInfoResponse), packet.Header.Type)
+	assert.Equal(t, tickInfo, packet.Data)
+
+	server.SetQuorumVotes(999, types.QuorumVotes{{ComputorIndex: 1, Tick: 999}, {ComputorIndex: 2, Tick: 999}})
+	request := struct {
+		Tick      uint32
+		VoteFlags [(types.NumberOfComputors + 7) / 8]byte
+	}{Tick: 999}
+
+	var packets types.Packets
+	err = client.Do(context.Background(), types.QuorumTickRequest, request, &packets)
+	require.NoError(t, err)
+	require.Len(t, packets, 2)
+	assert.Equal(t, uint16(2), packets[1].Data.(types.QuorumTickVote).ComputorIndex)
+}
+
+func TestPacketQueue_Overflow(t *testing.T) {
+	queue := newPacketQueue()
+	queue.maxBytes = 100
+
+	queue.push(make([]byte, 60))
+	packet, err := queue.pop(context.Background(), time.Now().Add(time.Second))
+	require.NoError(t, err)
+	assert.Len(t, packet, 60)
+
+	// consumed packets no longer count against the bound
+	queue.push(make([]byte, 60))
+	queue.push(make([]byte, 60))
+	queue.push(make([]byte, 10))
+	_, err = queue.pop(context.Background(), time.Now().Add(time.Second))
+	assert.True(t, errors.Is(err, types.ErrResponseTooLarge))
+}
+
+func TestPacketStreamReader_ResponseBudget(t *testing.T) {
+	queue := newPacketQueue()
+	reader := packetStreamReader{ctx: context.Background(), deadline: time.Now().Add(time.Second), queue: queue, maxBytes: 100}
+
+	// the queue is drained as the response is read, the budget still counts every packet
+	queue.push(make([]byte, 60))
+	_, err := io.ReadFull(&reader, make([]byte, 60))
+	require.NoError(t, err)
+
+	queue.push(make([]byte, 60))
+	_, err = io.ReadFull(&reader, make([]byte, 60))
+	assert.True(t, errors.Is(err, types.ErrResponseTooLarge))
+}
@@ -0,0 +1,243 @@
+package qubic
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// NodeSource lists the nodes a Pool connects to. Addresses are hosts, optionally with a port that overrides
+// PoolConfig.NodePort.
+type NodeSource interface {
+	Nodes(ctx context.Context) ([]string, error)
+}
+
+// MaxTickReporter is implemented by node sources that know the highest tick of the network. The pool measures the
+// lag of the nodes against it.
+type MaxTickReporter interface {
+	MaxTick() uint32
+}
+
+// PeerLearner is implemented by node sources that grow with the peers advertised by connected nodes.
+type PeerLearner interface {
+	Learn(peers types.PublicPeers)
+}
+
+// StaticNodeSource is a fixed list of nodes.
+type StaticNodeSource []string
+
+func (s StaticNodeSource) Nodes(ctx context.Context) ([]string, error) {
+	if len(s) == 0 {
+		return nil, errors.New("no static nodes")
+	}
+
+	return append([]string(nil), s...), nil
+}
+
+// FetcherNodeSource lists the reliable nodes of a node fetcher status endpoint.
+type FetcherNodeSource struct {
+	url        string
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	maxTick uint32
+}
+
+// NewFetcherNodeSource uses http.DefaultClient when httpClient is nil.
+func NewFetcherNodeSource(url string, httpClient *http.Client) *FetcherNodeSource {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return &FetcherNodeSource{url: url, httpClient: httpClient}
+}
+
+type statusResponse struct {
+	MaxTick          uint32         `json:"max_tick"`
+	LastUpdate       int64          `json:"last_update"`
+	ReliableNodes    []nodeResponse `json:"reliable_nodes"`
+	MostReliableNode nodeResponse   `json:"most_reliable_node"`
+}
+
+type nodeResponse struct {
+	Address    string            `json:"address"`
+	Peers      types.PublicPeers `json:"peers"`
+	LastTick   uint32            `json:"last_tick"`
+	LastUpdate int64             `json:"last_update"`
+}
+
+func (fs *FetcherNodeSource) Nodes(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fs.url, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating new request")
+	}
+
+	res, err := fs.httpClient.Do(req)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting peers from node fetcher")
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("node fetcher responded with status %d", res.StatusCode)
+	}
+
+	var resp statusResponse
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading response body")
+	}
+
+	err = json.Unmarshal(body, &resp)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshalling response")
+	}
+
+	if len(resp.ReliableNodes) == 0 {
+		return nil, errors.New("node fetcher returned no reliable nodes")
+	}
+
+	fs.mu.Lock()
+	fs.maxTick = resp.MaxTick
+	fs.mu.Unlock()
+
+	nodes := make([]string, 0, len(resp.ReliableNodes))
+	for _, node := range resp.ReliableNodes {
+		nodes = append(nodes, node.Address)
+	}
+
+	return nodes, nil
+}
+
+// MaxTick returns the max tick of the last fetched status.
+func (fs *FetcherNodeSource) MaxTick() uint32 {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	return fs.maxTick
+}
+
+// DNSNodeSource resolves host names, each to every address it points to. A name may carry a port, which is kept on
+// the resolved addresses.
+type DNSNodeSource struct {
+	Names []string
+	// Resolver is net.DefaultResolver when nil.
+	Resolver *net.Resolver
+}
+
+func (ds *DNSNodeSource) Nodes(ctx context.Context) ([]string, error) {
+	resolver := ds.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	seen := make(map[string]bool)
+	var nodes []string
+	var lastErr error
+	for _, name := range ds.Names {
+		host, port, err := net.SplitHostPort(name)
+		if err != nil {
+			host, port = name, ""
+		}
+
+		addrs, err := resolver.LookupHost(ctx, host)
+		if err != nil {
+			lastErr = errors.Wrapf(err, "resolving %s", host)
+			continue
+		}
+
+		for _, addr := range addrs {
+			if port != "" {
+				addr = net.JoinHostPort(addr, port)
+			}
+			if !seen[addr] {
+				seen[addr] = true
+				nodes = append(nodes, addr)
+			}
+		}
+	}
+
+	if len(nodes) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, errors.New("no node resolved")
+	}
+
+	return nodes, nil
+}
+
+// PeerNodeSource lists the nodes of a seed source together with the peers learned from connected nodes. The pool
+// teaches it the Client.Peers of every client it connects, so the set of known nodes grows over time.
+type PeerNodeSource struct {
+	seed NodeSource
+
+	mu      sync.Mutex
+	learned map[string]bool
+}
+
+func NewPeerNodeSource(seed NodeSource) *PeerNodeSource {
+	return &PeerNodeSource{seed: seed, learned: make(map[string]bool)}
+}
+
+// Learn adds the peers to the known nodes.
+func (ps *PeerNodeSource) Learn(peers types.PublicPeers) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, peer := range peers {
+		if ip := net.ParseIP(peer); ip == nil || ip.IsUnspecified() {
+			continue
+		}
+		ps.learned[peer] = true
+	}
+}
+
+// Nodes returns the seed nodes followed by the learned ones. A failing seed is not an error as long as peers were
+// learned before.
+func (ps *PeerNodeSource) Nodes(ctx context.Context) ([]string, error) {
+	seeds, seedErr := ps.seed.Nodes(ctx)
+
+	ps.mu.Lock()
+	learned := make([]string, 0, len(ps.learned))
+	for peer := range ps.learned {
+		learned = append(learned, peer)
+	}
+	ps.mu.Unlock()
+	sort.Strings(learned)
+
+	seen := make(map[string]bool, len(seeds))
+	nodes := make([]string, 0, len(seeds)+len(learned))
+	for _, node := range append(seeds, learned...) {
+		if !seen[node] {
+			seen[node] = true
+			nodes = append(nodes, node)
+		}
+	}
+
+	if len(nodes) == 0 {
+		if seedErr != nil {
+			return nil, errors.Wrap(seedErr, "getting seed nodes")
+		}
+		return nil, errors.New("no seed or learned node")
+	}
+
+	return nodes, nil
+}
+
+// MaxTick forwards the max tick of the seed source, if it knows it.
+func (ps *PeerNodeSource) MaxTick() uint32 {
+	reporter, ok := ps.seed.(MaxTickReporter)
+	if !ok {
+		return 0
+	}
+
+	return reporter.MaxTick()
+}
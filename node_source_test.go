@@ -0,0 +1,102 @@
+package qubic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticNodeSource_Nodes(t *testing.T) {
+	nodes, err := StaticNodeSource{"1.2.3.4", "5.6.7.8:31841"}.Nodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4", "5.6.7.8:31841"}, nodes)
+
+	_, err = StaticNodeSource{}.Nodes(context.Background())
+	assert.Error(t, err)
+}
+
+func TestFetcherNodeSource_Nodes(t *testing.T) {
+	resp := statusResponse{MaxTick: 100, ReliableNodes: []nodeResponse{{Address: "1.2.3.4"}, {Address: "5.6.7.8"}}}
+	fetcher := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer fetcher.Close()
+
+	source := NewFetcherNodeSource(fetcher.URL, nil)
+	nodes, err := source.Nodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4", "5.6.7.8"}, nodes)
+	assert.Equal(t, uint32(100), source.MaxTick())
+
+	resp = statusResponse{MaxTick: 200}
+	_, err = source.Nodes(context.Background())
+	assert.Error(t, err)
+}
+
+func TestDNSNodeSource_Nodes(t *testing.T) {
+	source := DNSNodeSource{Names: []string{"127.0.0.1:31841", "127.0.0.1:31841", "127.0.0.2"}}
+	nodes, err := source.Nodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"127.0.0.1:31841", "127.0.0.2"}, nodes)
+
+	_, err = (&DNSNodeSource{}).Nodes(context.Background())
+	assert.Error(t, err)
+}
+
+func TestPeerNodeSource_Nodes(t *testing.T) {
+	source := NewPeerNodeSource(StaticNodeSource{"1.2.3.4"})
+	source.Learn(types.PublicPeers{"5.6.7.8", "1.2.3.4", "0.0.0.0", "not an ip"})
+
+	nodes, err := source.Nodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"1.2.3.4", "5.6.7.8"}, nodes)
+
+	// learned peers outlive a failing seed
+	source = NewPeerNodeSource(StaticNodeSource{})
+	_, err = source.Nodes(context.Background())
+	assert.Error(t, err)
+	source.Learn(types.PublicPeers{"5.6.7.8"})
+	nodes, err = source.Nodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"5.6.7.8"}, nodes)
+}
+
+func TestPool_NodeSource(t *testing.T) {
+	node := newTestNode(t, 100)
+	require.NoError(t, node.SetPeers([]string{"10.0.0.1", "10.0.0.2"}))
+	source := NewPeerNodeSource(StaticNodeSource{node.Addr()})
+
+	p, err := NewPoolConnection(PoolConfig{
+		MaxCap:      5,
+		MaxIdle:     5,
+		IdleTimeout: time.Minute,
+		NodeSource:  source,
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	client, err := p.Get()
+	require.NoError(t, err)
+	assert.Equal(t, node.Addr(), client.addr)
+	require.NoError(t, p.Put(client))
+
+	nodes, err := source.Nodes(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, []string{node.Addr(), "10.0.0.1", "10.0.0.2"}, nodes)
+}
+
+func TestPool_EmptyNodeSource(t *testing.T) {
+	p, err := NewPoolConnection(PoolConfig{MaxCap: 5, MaxIdle: 5, NodeSource: StaticNodeSource{}})
+	require.NoError(t, err)
+	defer p.Release()
+
+	_, err = p.Get()
+	assert.Error(t, err)
+}
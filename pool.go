@@ -2,35 +2,62 @@ package qubic
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"github.com/pkg/errors"
-	"github.com/qubic/go-node-connector/types"
 	"github.com/silenceper/pool"
-	"io"
-	"math/rand"
-	"net/http"
+	"net"
+	"sync"
 	"time"
 )
 
 type PoolConfig struct {
-	InitialCap         int
-	MaxCap             int
-	MaxIdle            int
-	IdleTimeout        time.Duration
-	NodeFetcherUrl     string
+	InitialCap  int
+	MaxCap      int
+	MaxIdle     int
+	IdleTimeout time.Duration
+	// NodeFetcherUrl is the node fetcher status endpoint used when NodeSource is nil.
+	NodeFetcherUrl string
+	// NodeFetcherTimeout bounds listing the nodes and connecting to one, 5 seconds when zero.
 	NodeFetcherTimeout time.Duration
 	NodePort           string
+	// NodeSource lists the nodes to connect to, a FetcherNodeSource on NodeFetcherUrl when nil.
+	NodeSource NodeSource
+	// HealthCheckInterval is the period at which every node is probed with GetTickInfo. Zero disables the background
+	// probes, nodes are then only probed when connecting.
+	HealthCheckInterval time.Duration
+	// MaxTickLag is the number of ticks a node may be behind the reference tick before it is avoided, 5 when zero. The
+	// reference is the highest recently probed tick, bounded by the median of the other nodes and the source max tick.
+	MaxTickLag uint32
+	// CircuitBreakerThreshold is the number of consecutive failures after which a node is skipped, 3 when zero.
+	CircuitBreakerThreshold int
+	// CircuitBreakerCooldown is the time a node is skipped once its circuit is open, 30 seconds when zero.
+	CircuitBreakerCooldown time.Duration
+	// MaxRetries is the number of times DoIdempotent retries a failed call, 2 when zero. Negative disables retries.
+	MaxRetries int
+	// RetryBackoff is the wait before the first retry of DoIdempotent, doubled on every retry. 100ms when zero.
+	RetryBackoff time.Duration
 }
 
 func NewPoolConnection(config PoolConfig) (*Pool, error) {
-	pcf := newPoolConnectionFactory(config.NodeFetcherTimeout, config.NodeFetcherUrl, config.NodePort)
+	// background probes refresh every node each interval, their ticks stay comparable until the next round
+	health := newNodeHealth(config.MaxTickLag, config.CircuitBreakerThreshold, config.CircuitBreakerCooldown, 2*config.HealthCheckInterval)
+	source := config.NodeSource
+	if source == nil {
+		source = NewFetcherNodeSource(config.NodeFetcherUrl, nil)
+	}
+	timeout := config.NodeFetcherTimeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	pcf := newPoolConnectionFactory(timeout, source, config.NodePort, health)
 	cfg := pool.Config{
 		InitialCap: config.InitialCap,
 		MaxIdle:    config.MaxIdle,
 		MaxCap:     config.MaxCap,
 		Factory:    pcf.Connect,
 		Close:      pcf.Close,
+		Ping:       pcf.Ping,
 		//The maximum idle time of the connection, the connection exceeding this time will be closed, which can avoid the problem of automatic failure when connecting to EOF when idle
 		IdleTimeout: config.IdleTimeout,
 	}
@@ -39,13 +66,40 @@ func NewPoolConnection(config PoolConfig) (*Pool, error) {
 		return nil, errors.Wrap(err, "creating pool")
 	}
 
-	p := Pool{chPool: chPool}
+	p := Pool{
+		chPool:       chPool,
+		health:       health,
+		factory:      pcf,
+		maxRetries:   config.MaxRetries,
+		retryBackoff: config.RetryBackoff,
+		stop:         make(chan struct{}),
+	}
+	if p.maxRetries == 0 {
+		p.maxRetries = defaultMaxRetries
+	}
+	if p.retryBackoff <= 0 {
+		p.retryBackoff = defaultRetryBackoff
+	}
+
+	if config.HealthCheckInterval > 0 {
+		p.wg.Add(1)
+		go p.healthCheckLoop(config.HealthCheckInterval)
+	}
 
 	return &p, nil
 }
 
 type Pool struct {
-	chPool pool.Pool
+	chPool  pool.Pool
+	health  *nodeHealth
+	factory *poolConnectionFactory
+
+	maxRetries   int
+	retryBackoff time.Duration
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	wg       sync.WaitGroup
 }
 
 func (p *Pool) Get() (*Client, error) {
@@ -56,7 +110,16 @@ func (p *Pool) Get() (*Client, error) {
 	return v.(*Client), nil
 }
 
+// Put gives the client back to the pool, or closes it if its node became unhealthy in the meantime.
 func (p *Pool) Put(c *Client) error {
+	if !p.health.healthy(c.addr) {
+		err := p.chPool.Close(c)
+		if err != nil {
+			return errors.Wrap(err, "closing unhealthy qubic pooled client connection")
+		}
+		return nil
+	}
+
 	err := p.chPool.Put(c)
 	if err != nil {
 		return errors.Wrap(err, "putting qubic pooled client connection")
@@ -65,7 +128,10 @@ func (p *Pool) Put(c *Client) error {
 	return nil
 }
 
+// Close closes a client that failed. The failure counts against the health of its node.
 func (p *Pool) Close(c *Client) error {
+	p.health.recordFailure(c.addr)
+
 	err := p.chPool.Close(c)
 	if err != nil {
 		return errors.Wrap(err, "closing qubic pool")
@@ -74,75 +140,182 @@ func (p *Pool) Close(c *Client) error {
 	return nil
 }
 
+// NodeStats returns the health of every node the pool has seen, sorted by address.
+func (p *Pool) NodeStats() []NodeStats {
+	return p.health.stats()
+}
+
+// Release stops the health checks and closes every idle client.
+func (p *Pool) Release() {
+	p.stopOnce.Do(func() { close(p.stop) })
+	p.wg.Wait()
+	p.chPool.Release()
+}
+
+func (p *Pool) healthCheckLoop(interval time.Duration) {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), interval)
+		p.factory.probeAll(ctx)
+		cancel()
+	}
+}
+
 type poolConnectionFactory struct {
 	nodeFetcherTimeout time.Duration
-	nodeFetcherUrl     string
+	source             NodeSource
 	nodePort           string
+	health             *nodeHealth
 }
 
-func newPoolConnectionFactory(nodeFetcherTimeout time.Duration, nodeFetcherUrl string, nodePort string) *poolConnectionFactory {
-	return &poolConnectionFactory{nodeFetcherTimeout: nodeFetcherTimeout, nodeFetcherUrl: nodeFetcherUrl, nodePort: nodePort}
+func newPoolConnectionFactory(nodeFetcherTimeout time.Duration, source NodeSource, nodePort string, health *nodeHealth) *poolConnectionFactory {
+	return &poolConnectionFactory{nodeFetcherTimeout: nodeFetcherTimeout, source: source, nodePort: nodePort, health: health}
 }
 
+// Connect connects to one of the best scored nodes, failing over to the next one when a node can't be reached or is
+// behind.
 func (pcf *poolConnectionFactory) Connect() (interface{}, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), pcf.nodeFetcherTimeout)
 	defer cancel()
 
-	peer, err := pcf.getNewRandomPeer(ctx)
+	nodes, err := pcf.getNodes(ctx)
 	if err != nil {
-		return nil, errors.Wrap(err, "getting new random peer")
+		return nil, errors.Wrap(err, "getting nodes")
 	}
 
-	client, err := NewClient(ctx, peer, pcf.nodePort)
-	if err != nil {
-		return nil, errors.Wrap(err, "creating qubic client")
+	candidates := nodes
+	for len(candidates) > 0 {
+		peer, err := pcf.health.pick(candidates)
+		if err != nil {
+			return nil, errors.Wrap(err, "picking node")
+		}
+		candidates = without(candidates, peer)
+
+		client, err := pcf.probe(ctx, peer)
+		if err != nil {
+			fmt.Printf("probing %s: %s\n", peer, err.Error())
+			continue
+		}
+
+		if !pcf.health.healthy(peer) {
+			client.Close()
+			continue
+		}
+
+		if learner, ok := pcf.source.(PeerLearner); ok {
+			learner.Learn(client.Peers)
+		}
+
+		fmt.Printf("connected to: %s\n", peer)
+		return client, nil
 	}
 
-	fmt.Printf("connected to: %s\n", peer)
-	return client, nil
+	return nil, errors.Wrapf(ErrNoHealthyNode, "tried %d nodes", len(nodes))
 }
 
 func (pcf *poolConnectionFactory) Close(v interface{}) error { return v.(*Client).Close() }
 
-type statusResponse struct {
-	MaxTick          uint32         `json:"max_tick"`
-	LastUpdate       int64          `json:"last_update"`
-	ReliableNodes    []nodeResponse `json:"reliable_nodes"`
-	MostReliableNode nodeResponse   `json:"most_reliable_node"`
-}
+// Ping keeps idle clients of nodes that became unhealthy from being handed out.
+func (pcf *poolConnectionFactory) Ping(v interface{}) error {
+	c := v.(*Client)
+	if !pcf.health.healthy(c.addr) {
+		return errors.Errorf("node %s is unhealthy", c.addr)
+	}
 
-type nodeResponse struct {
-	Address    string            `json:"address"`
-	Peers      types.PublicPeers `json:"peers"`
-	LastTick   uint32            `json:"last_tick"`
-	LastUpdate int64             `json:"last_update"`
+	return nil
 }
 
-func (pcf *poolConnectionFactory) getNewRandomPeer(ctx context.Context) (string, error) {
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pcf.nodeFetcherUrl, nil)
+// probe connects to the node and records its GetTickInfo round trip and tick.
+func (pcf *poolConnectionFactory) probe(ctx context.Context, address string) (*Client, error) {
+	host, port := splitNodeAddress(address, pcf.nodePort)
+
+	client, err := NewClient(ctx, host, port)
 	if err != nil {
-		return "", errors.Wrap(err, "creating new request")
+		pcf.health.recordFailure(address)
+		return nil, errors.Wrap(err, "creating qubic client")
+	}
+	client.addr = address
+
+	start := time.Now()
+	tickInfo, err := client.GetTickInfo(ctx)
+	if err != nil {
+		pcf.health.recordFailure(address)
+		client.Close()
+		return nil, errors.Wrap(err, "getting tick info")
 	}
+	pcf.health.recordProbe(address, time.Since(start), tickInfo.Tick)
+
+	return client, nil
+}
 
-	res, err := http.DefaultClient.Do(req)
+func (pcf *poolConnectionFactory) probeAll(ctx context.Context) {
+	nodes, err := pcf.getNodes(ctx)
 	if err != nil {
-		return "", errors.Wrap(err, "getting peers from node fetcher")
+		fmt.Printf("health check: getting nodes: %s\n", err.Error())
+		return
 	}
 
-	var resp statusResponse
-	body, err := io.ReadAll(res.Body)
+	var wg sync.WaitGroup
+	for _, node := range nodes {
+		wg.Add(1)
+		go func(node string) {
+			defer wg.Done()
+
+			client, err := pcf.probe(ctx, node)
+			if err != nil {
+				return
+			}
+			client.Close()
+		}(node)
+	}
+	wg.Wait()
+}
+
+// splitNodeAddress accepts node addresses with or without a port.
+func splitNodeAddress(address, defaultPort string) (string, string) {
+	host, port, err := net.SplitHostPort(address)
 	if err != nil {
-		return "", errors.Wrap(err, "reading response body")
+		return address, defaultPort
 	}
 
-	err = json.Unmarshal(body, &resp)
+	return host, port
+}
+
+func without(nodes []string, node string) []string {
+	remaining := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		if n != node {
+			remaining = append(remaining, n)
+		}
+	}
+
+	return remaining
+}
+
+// getNodes lists the nodes of the source and raises the reference tick to the max tick of the source, if it knows it.
+func (pcf *poolConnectionFactory) getNodes(ctx context.Context) ([]string, error) {
+	nodes, err := pcf.source.Nodes(ctx)
 	if err != nil {
-		return "", errors.Wrap(err, "unmarshalling response")
+		return nil, errors.Wrap(err, "listing nodes")
 	}
 
-	peer := resp.ReliableNodes[rand.Intn(len(resp.ReliableNodes))]
+	if len(nodes) == 0 {
+		return nil, errors.New("node source returned no nodes")
+	}
 
-	fmt.Printf("Got %d new peers. Selected random %s\n", len(resp.ReliableNodes), peer.Address)
+	if reporter, ok := pcf.source.(MaxTickReporter); ok {
+		pcf.health.setMaxTick(reporter.MaxTick())
+	}
 
-	return peer.Address, nil
+	return nodes, nil
 }
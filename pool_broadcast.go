@@ -0,0 +1,125 @@
+package qubic
+
+import (
+	"context"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+type BroadcastResult struct {
+	TxID string
+	// Accepted are the peers the transaction was written to.
+	Accepted []string
+	// Failed are the peers that could not be reached, with the reason.
+	Failed map[string]error
+	// Err is set when the transaction could not be sent to any peer or is invalid.
+	Err error
+}
+
+// Broadcast sends the signed transaction to n distinct peers in parallel, each over its own connection. Peers that
+// fail are replaced by other healthy peers of the node source while there are some left, so a dead peer doesn't cost
+// one of the n copies. n must be positive.
+func (p *Pool) Broadcast(ctx context.Context, tx types.Transaction, n int) BroadcastResult {
+	result := BroadcastResult{Failed: make(map[string]error)}
+
+	if n <= 0 {
+		result.Err = errors.Errorf("number of peers must be positive, got %d", n)
+		return result
+	}
+
+	if tx.Signature == [64]byte{} {
+		result.Err = types.ErrTxNotSigned
+		return result
+	}
+
+	id, err := tx.ID()
+	if err != nil {
+		result.Err = errors.Wrap(err, "getting tx id")
+		return result
+	}
+	result.TxID = id
+
+	rawTx, err := tx.MarshallBinary()
+	if err != nil {
+		result.Err = errors.Wrap(err, "marshalling tx")
+		return result
+	}
+
+	candidates, err := p.factory.getNodes(ctx)
+	if err != nil {
+		result.Err = errors.Wrap(err, "getting nodes")
+		return result
+	}
+
+	for len(result.Accepted) < n && len(candidates) > 0 && ctx.Err() == nil {
+		var peers []string
+		for len(peers) < n-len(result.Accepted) && len(candidates) > 0 {
+			peer, err := p.health.pick(candidates)
+			if err != nil {
+				break
+			}
+			candidates = without(candidates, peer)
+			peers = append(peers, peer)
+		}
+
+		if len(peers) == 0 {
+			break
+		}
+
+		errs := p.sendToPeers(ctx, peers, rawTx)
+		for i, peer := range peers {
+			if errs[i] != nil {
+				result.Failed[peer] = errs[i]
+				continue
+			}
+			result.Accepted = append(result.Accepted, peer)
+		}
+	}
+
+	if len(result.Accepted) == 0 {
+		result.Err = errors.Errorf("transaction not sent to any of the %d peers tried", len(result.Failed))
+	}
+
+	return result
+}
+
+func (p *Pool) sendToPeers(ctx context.Context, peers []string, rawTx []byte) []error {
+	errs := make([]error, len(peers))
+
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(i int, peer string) {
+			defer wg.Done()
+
+			errs[i] = p.sendToPeer(ctx, peer, rawTx)
+			if errs[i] != nil {
+				p.health.recordFailure(peer)
+			}
+		}(i, peer)
+	}
+	wg.Wait()
+
+	return errs
+}
+
+func (p *Pool) sendToPeer(ctx context.Context, peer string, rawTx []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, p.factory.nodeFetcherTimeout)
+	defer cancel()
+
+	host, port := splitNodeAddress(peer, p.factory.nodePort)
+	client, err := NewClient(ctx, host, port)
+	if err != nil {
+		return errors.Wrap(err, "creating qubic client")
+	}
+	defer client.Close()
+
+	err = client.SendRawTransaction(ctx, rawTx)
+	if err != nil {
+		return errors.Wrap(err, "sending tx")
+	}
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package qubic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPool_Broadcast(t *testing.T) {
+	nodes := []*qubictest.Server{newTestNode(t, 100), newTestNode(t, 100), newTestNode(t, 100)}
+	dead := newTestNode(t, 100)
+	require.NoError(t, dead.Close())
+
+	source := StaticNodeSource{dead.Addr()}
+	for _, node := range nodes {
+		source = append(source, node.Addr())
+	}
+
+	p, err := NewPoolConnection(PoolConfig{MaxCap: 5, MaxIdle: 5, NodeSource: source})
+	require.NoError(t, err)
+	defer p.Release()
+
+	tx := newSignedTestTx(t, 105)
+	id, err := tx.ID()
+	require.NoError(t, err)
+
+	result := p.Broadcast(context.Background(), tx, 3)
+	require.NoError(t, result.Err)
+	assert.Equal(t, id, result.TxID)
+	assert.Len(t, result.Accepted, 3)
+	assert.NotContains(t, result.Accepted, dead.Addr())
+
+	require.Eventually(t, func() bool {
+		for _, node := range nodes {
+			if len(node.BroadcastTransactions()) != 1 {
+				return false
+			}
+		}
+		return true
+	}, 2*time.Second, time.Millisecond)
+
+	result = p.Broadcast(context.Background(), tx, 10)
+	require.NoError(t, result.Err)
+	assert.Len(t, result.Accepted, 3)
+	assert.Contains(t, result.Failed, dead.Addr())
+
+	result = p.Broadcast(context.Background(), types.Transaction{Tick: 105}, 3)
+	assert.Error(t, result.Err)
+	assert.Empty(t, result.Accepted)
+
+	result = p.Broadcast(context.Background(), tx, 0)
+	assert.Error(t, result.Err)
+	assert.Empty(t, result.Accepted)
+}
@@ -0,0 +1,120 @@
+package qubic
+
+import (
+	"context"
+	"io"
+	"net"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+const (
+	defaultMaxRetries   = 2
+	defaultRetryBackoff = 100 * time.Millisecond
+)
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error returned to Pool.DoIdempotent as not worth retrying, e.g. because another node would
+// answer the same.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// Do runs fn once with a pooled client, unless ctx is already done. The client is closed and the failure counts
+// against its node when fn fails with a transport or decoding error, otherwise the client is put back. A failed call is
+// not retried, use it to broadcast or submit transactions.
+func (p *Pool) Do(ctx context.Context, fn func(client *Client) error) error {
+	return p.do(ctx, fn)
+}
+
+// DoIdempotent runs fn like Do, but retries a failed call with a client taken anew from the pool after an exponential
+// backoff, unless the error is marked with Permanent or comes from ctx. The retry may run on the same node when it is
+// still healthy. fn may run several times, only use it for calls that are safe to repeat such as queries.
+func (p *Pool) DoIdempotent(ctx context.Context, fn func(client *Client) error) error {
+	backoff := p.retryBackoff
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		err = p.do(ctx, fn)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *permanentError
+		if errors.As(err, &permanent) || ctx.Err() != nil || attempt >= p.maxRetries {
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		}
+		backoff *= 2
+	}
+}
+
+func (p *Pool) do(ctx context.Context, fn func(client *Client) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	client, err := p.Get()
+	if err != nil {
+		return err
+	}
+
+	err = fn(client)
+	if err == nil {
+		return p.Put(client)
+	}
+
+	if isNodeError(err) || client.broken() {
+		p.Close(client)
+	} else {
+		p.Put(client)
+	}
+
+	return err
+}
+
+// isNodeError tells whether err comes from the connection or the response of the node, rather than from the caller
+// or the context. Errors marked with Permanent are never held against the node.
+func isNodeError(err error) bool {
+	var permanent *permanentError
+	if errors.As(err, &permanent) {
+		return false
+	}
+
+	var netErr net.Error
+	var unexpected types.ErrUnexpectedPacket
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, ErrTimeout), errors.Is(err, ErrClientClosed):
+		return true
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		return true
+	case errors.Is(err, types.ErrInvalidPacketSize), errors.Is(err, types.ErrResponseTooLarge):
+		return true
+	case errors.As(err, &netErr), errors.As(err, &unexpected):
+		return true
+	}
+
+	return false
+}
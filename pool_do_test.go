@@ -0,0 +1,149 @@
+package qubic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPool(t *testing.T, maxRetries int) *Pool {
+	node := newTestNode(t, 100)
+	fetcher := newTestNodeFetcher(t, 100, node)
+
+	p, err := NewPoolConnection(PoolConfig{
+		MaxCap:             5,
+		MaxIdle:            5,
+		IdleTimeout:        time.Minute,
+		NodeFetcherUrl:     fetcher.URL,
+		NodeFetcherTimeout: 5 * time.Second,
+		MaxRetries:         maxRetries,
+		RetryBackoff:       time.Millisecond,
+	})
+	require.NoError(t, err)
+	t.Cleanup(p.Release)
+
+	return p
+}
+
+func TestPool_Do(t *testing.T) {
+	p := newTestPool(t, 0)
+
+	// calls are not retried unless they are idempotent
+	calls := 0
+	err := p.Do(context.Background(), func(client *Client) error {
+		calls++
+		return ErrTimeout
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = p.Do(ctx, func(client *Client) error {
+		calls++
+		return nil
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls)
+
+	err = p.Do(context.Background(), func(client *Client) error {
+		_, err := client.GetTickInfo(context.Background())
+		return err
+	})
+	require.NoError(t, err)
+}
+
+func TestPool_DoIdempotent(t *testing.T) {
+	p := newTestPool(t, 0)
+
+	var clients []*Client
+	err := p.DoIdempotent(context.Background(), func(client *Client) error {
+		clients = append(clients, client)
+		if len(clients) == 1 {
+			return errors.Wrap(ErrTimeout, "broken connection")
+		}
+
+		_, err := client.GetTickInfo(context.Background())
+		return err
+	})
+	require.NoError(t, err)
+	require.Len(t, clients, 2)
+	assert.NotSame(t, clients[0], clients[1])
+
+	// the failed client is closed, the healthy one is back in the pool
+	_, err = clients[0].GetTickInfo(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, 1, p.chPool.Len())
+}
+
+func TestPool_DoKeepsClientOnCallerError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+	}{
+		{name: "caller error", err: errors.New("no such asset")},
+		{name: "permanent error", err: Permanent(errors.Wrap(ErrTimeout, "answered the same by every node"))},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newTestPool(t, 0)
+
+			var failed *Client
+			err := p.Do(context.Background(), func(client *Client) error {
+				failed = client
+				return tc.err
+			})
+			require.Error(t, err)
+
+			// the client is back in the pool and its node is not penalised
+			_, err = failed.GetTickInfo(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, 1, p.chPool.Len())
+			for _, stats := range p.NodeStats() {
+				assert.Zero(t, stats.ErrorRate)
+			}
+		})
+	}
+}
+
+func TestPool_DoIdempotentGivesUp(t *testing.T) {
+	failure := errors.New("failure")
+
+	testCases := []struct {
+		name          string
+		maxRetries    int
+		err           error
+		cancel        bool
+		expectedCalls int
+	}{
+		{name: "retries exhausted", maxRetries: 2, err: failure, expectedCalls: 3},
+		{name: "retries disabled", maxRetries: -1, err: failure, expectedCalls: 1},
+		{name: "permanent error", maxRetries: 2, err: Permanent(failure), expectedCalls: 1},
+		{name: "context canceled", maxRetries: 2, err: failure, cancel: true, expectedCalls: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newTestPool(t, tc.maxRetries)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			calls := 0
+			err := p.DoIdempotent(ctx, func(client *Client) error {
+				calls++
+				if tc.cancel {
+					cancel()
+				}
+				return tc.err
+			})
+			assert.ErrorIs(t, err, failure)
+			assert.Equal(t, tc.expectedCalls, calls)
+		})
+	}
+}
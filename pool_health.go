@@ -0,0 +1,283 @@
+package qubic
+
+import (
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultMaxTickLag              = 5
+	defaultCircuitBreakerThreshold = 3
+	defaultCircuitBreakerCooldown  = 30 * time.Second
+	// defaultProbeTTL is how long the tick of a probe is compared with the other nodes when there are no background
+	// health checks. Ticks move on, so older probes tell nothing about the lag of a node.
+	defaultProbeTTL = 5 * time.Second
+
+	// healthAverageWeight is the weight of the latest sample in the latency and error rate moving averages
+	healthAverageWeight = 0.3
+	// healthPickTop is the number of best scored nodes a new connection is randomly picked from, so that the load is
+	// spread instead of always hitting the single best node
+	healthPickTop = 3
+)
+
+// NodeStats is the health of a node as seen by the pool.
+type NodeStats struct {
+	Address string
+	// Latency is the moving average of the GetTickInfo probe round trip.
+	Latency time.Duration
+	// ErrorRate is the moving average of failed probes and requests, between 0 and 1.
+	ErrorRate float64
+	Tick      uint32
+	// Lag is the number of ticks the node is behind the reference tick of the pool, zero when its last probe is stale.
+	Lag uint32
+	// CircuitOpen is true while the node is skipped after too many consecutive failures.
+	CircuitOpen bool
+}
+
+type nodeState struct {
+	probed              bool
+	probedAt            time.Time
+	latency             time.Duration
+	errorRate           float64
+	tick                uint32
+	consecutiveFailures int
+	openUntil           time.Time
+}
+
+// nodeHealth scores the nodes of a pool from the outcome of probes and requests. The lag of a node is only judged from
+// a probe younger than probeTTL, a node with a stale probe is eligible again so that connecting to it probes it anew.
+type nodeHealth struct {
+	maxTickLag              uint32
+	circuitBreakerThreshold int
+	circuitBreakerCooldown  time.Duration
+	probeTTL                time.Duration
+
+	mu              sync.Mutex
+	nodes           map[string]*nodeState
+	sourceMaxTick   uint32
+	sourceMaxTickAt time.Time
+	now             func() time.Time
+}
+
+func newNodeHealth(maxTickLag uint32, circuitBreakerThreshold int, circuitBreakerCooldown, probeTTL time.Duration) *nodeHealth {
+	if maxTickLag == 0 {
+		maxTickLag = defaultMaxTickLag
+	}
+	if circuitBreakerThreshold <= 0 {
+		circuitBreakerThreshold = defaultCircuitBreakerThreshold
+	}
+	if circuitBreakerCooldown <= 0 {
+		circuitBreakerCooldown = defaultCircuitBreakerCooldown
+	}
+	if probeTTL <= 0 {
+		probeTTL = defaultProbeTTL
+	}
+
+	return &nodeHealth{
+		maxTickLag:              maxTickLag,
+		circuitBreakerThreshold: circuitBreakerThreshold,
+		circuitBreakerCooldown:  circuitBreakerCooldown,
+		probeTTL:                probeTTL,
+		nodes:                   make(map[string]*nodeState),
+		now:                     time.Now,
+	}
+}
+
+func (nh *nodeHealth) state(address string) *nodeState {
+	state, ok := nh.nodes[address]
+	if !ok {
+		state = &nodeState{}
+		nh.nodes[address] = state
+	}
+
+	return state
+}
+
+// setMaxTick records the max tick reported by the node source, which bounds the reference tick.
+func (nh *nodeHealth) setMaxTick(tick uint32) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+
+	nh.sourceMaxTick = tick
+	nh.sourceMaxTickAt = nh.now()
+}
+
+func (nh *nodeHealth) recordProbe(address string, latency time.Duration, tick uint32) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+
+	state := nh.state(address)
+	if state.probed {
+		state.latency = time.Duration(healthAverageWeight*float64(latency) + (1-healthAverageWeight)*float64(state.latency))
+	} else {
+		state.latency = latency
+	}
+	state.probed = true
+	state.probedAt = nh.now()
+	state.tick = tick
+
+	nh.recordOutcome(state, true)
+}
+
+func (nh *nodeHealth) recordFailure(address string) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+
+	nh.recordOutcome(nh.state(address), false)
+}
+
+func (nh *nodeHealth) recordOutcome(state *nodeState, success bool) {
+	sample := 0.0
+	if !success {
+		sample = 1
+	}
+	state.errorRate = healthAverageWeight*sample + (1-healthAverageWeight)*state.errorRate
+
+	if success {
+		state.consecutiveFailures = 0
+		state.openUntil = time.Time{}
+		return
+	}
+
+	// once open, a single failure after the cooldown opens the circuit again
+	state.consecutiveFailures++
+	if state.consecutiveFailures >= nh.circuitBreakerThreshold {
+		state.openUntil = nh.now().Add(nh.circuitBreakerCooldown)
+	}
+}
+
+func (nh *nodeHealth) isFresh(state *nodeState) bool {
+	return state.probed && nh.now().Sub(state.probedAt) < nh.probeTTL
+}
+
+// referenceTick is the tick the lag of the nodes is measured against: the highest tick of the fresh probes bounded by
+// the median tick of the other nodes, or the max tick of the source when it is higher. A single node reporting a tick
+// far ahead can't make every other node look behind.
+func (nh *nodeHealth) referenceTick() uint32 {
+	var reference uint32
+	if nh.now().Sub(nh.sourceMaxTickAt) < nh.probeTTL {
+		reference = nh.sourceMaxTick
+	}
+
+	var ticks []uint32
+	for _, state := range nh.nodes {
+		if nh.isFresh(state) {
+			ticks = append(ticks, state.tick)
+		}
+	}
+	if len(ticks) < 2 {
+		return reference
+	}
+
+	sort.Slice(ticks, func(i, j int) bool { return ticks[i] < ticks[j] })
+	others := ticks[:len(ticks)-1]
+	probed := ticks[len(ticks)-1]
+	if median := others[len(others)/2]; probed > median {
+		probed = median
+	}
+	if probed > reference {
+		return probed
+	}
+
+	return reference
+}
+
+func (nh *nodeHealth) lag(state *nodeState, reference uint32) uint32 {
+	if !nh.isFresh(state) || state.tick >= reference {
+		return 0
+	}
+
+	return reference - state.tick
+}
+
+func (nh *nodeHealth) isHealthy(state *nodeState, reference uint32) bool {
+	return !nh.now().Before(state.openUntil) && nh.lag(state, reference) <= nh.maxTickLag
+}
+
+// healthy reports whether clients of the node can be handed out. Nodes never seen are healthy.
+func (nh *nodeHealth) healthy(address string) bool {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+
+	state, ok := nh.nodes[address]
+	if !ok {
+		return true
+	}
+
+	return nh.isHealthy(state, nh.referenceTick())
+}
+
+// score ranks healthy nodes, lower is better. Lag weighs the most since a node behind serves stale data.
+func (nh *nodeHealth) score(state *nodeState, reference uint32) float64 {
+	return float64(state.latency) * (1 + 4*state.errorRate) * (1 + float64(nh.lag(state, reference)))
+}
+
+// pick returns one of the best scored healthy nodes among the candidates. Probed nodes are preferred over nodes that
+// were never probed.
+func (nh *nodeHealth) pick(candidates []string) (string, error) {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+
+	type scoredNode struct {
+		address string
+		probed  bool
+		score   float64
+	}
+
+	reference := nh.referenceTick()
+	var healthy []scoredNode
+	for _, address := range candidates {
+		state, ok := nh.nodes[address]
+		if !ok {
+			state = &nodeState{}
+		}
+		if !nh.isHealthy(state, reference) {
+			continue
+		}
+		healthy = append(healthy, scoredNode{address: address, probed: state.probed, score: nh.score(state, reference)})
+	}
+
+	if len(healthy) == 0 {
+		return "", errors.Wrapf(ErrNoHealthyNode, "among %d candidates", len(candidates))
+	}
+
+	sort.SliceStable(healthy, func(i, j int) bool {
+		if healthy[i].probed != healthy[j].probed {
+			return healthy[i].probed
+		}
+		return healthy[i].score < healthy[j].score
+	})
+
+	top := healthPickTop
+	if top > len(healthy) {
+		top = len(healthy)
+	}
+
+	return healthy[rand.Intn(top)].address, nil
+}
+
+func (nh *nodeHealth) stats() []NodeStats {
+	nh.mu.Lock()
+	defer nh.mu.Unlock()
+
+	reference := nh.referenceTick()
+	stats := make([]NodeStats, 0, len(nh.nodes))
+	for address, state := range nh.nodes {
+		stats = append(stats, NodeStats{
+			Address:     address,
+			Latency:     state.latency,
+			ErrorRate:   state.errorRate,
+			Tick:        state.tick,
+			Lag:         nh.lag(state, reference),
+			CircuitOpen: nh.now().Before(state.openUntil),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Address < stats[j].Address })
+
+	return stats
+}
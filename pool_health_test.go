@@ -0,0 +1,104 @@
+package qubic
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNodeHealth_CircuitBreaker(t *testing.T) {
+	now := time.Now()
+	health := newNodeHealth(5, 2, time.Minute, 0)
+	health.now = func() time.Time { return now }
+
+	health.recordProbe("a", time.Millisecond, 100)
+	health.recordFailure("a")
+	assert.True(t, health.healthy("a"))
+
+	health.recordFailure("a")
+	assert.False(t, health.healthy("a"))
+	_, err := health.pick([]string{"a"})
+	assert.Error(t, err)
+
+	now = now.Add(time.Minute)
+	assert.True(t, health.healthy("a"))
+
+	// half open, the next failure opens the circuit again
+	health.recordFailure("a")
+	assert.False(t, health.healthy("a"))
+
+	now = now.Add(time.Minute)
+	health.recordProbe("a", time.Millisecond, 100)
+	health.recordFailure("a")
+	assert.True(t, health.healthy("a"))
+}
+
+func TestNodeHealth_Lag(t *testing.T) {
+	health := newNodeHealth(5, 0, 0, 0)
+	health.setMaxTick(100)
+
+	health.recordProbe("behind", time.Millisecond, 94)
+	health.recordProbe("close", 10*time.Millisecond, 95)
+	assert.False(t, health.healthy("behind"))
+	assert.True(t, health.healthy("close"))
+	assert.True(t, health.healthy("unknown"))
+
+	for i := 0; i < 10; i++ {
+		node, err := health.pick([]string{"behind", "close"})
+		require.NoError(t, err)
+		assert.Equal(t, "close", node)
+	}
+
+	// a single node ahead of the others doesn't move the reference
+	health.recordProbe("ahead", time.Millisecond, 0xFFFFFFF0)
+	assert.True(t, health.healthy("close"))
+
+	stats := health.stats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, "ahead", stats[0].Address)
+	assert.Equal(t, uint32(0), stats[0].Lag)
+	assert.Equal(t, uint32(6), stats[1].Lag)
+	assert.Equal(t, uint32(5), stats[2].Lag)
+}
+
+func TestNodeHealth_LagOfProbesAtDifferentTimes(t *testing.T) {
+	now := time.Now()
+	health := newNodeHealth(5, 0, 0, 10*time.Second)
+	health.now = func() time.Time { return now }
+
+	health.recordProbe("a", time.Millisecond, 100)
+	now = now.Add(8 * time.Second)
+	health.recordProbe("b", time.Millisecond, 110)
+	health.recordProbe("c", time.Millisecond, 110)
+	assert.False(t, health.healthy("a"))
+
+	// the probe of a is stale, its tick tells nothing about its lag anymore
+	now = now.Add(3 * time.Second)
+	assert.True(t, health.healthy("a"))
+	node, err := health.pick([]string{"a"})
+	require.NoError(t, err)
+	assert.Equal(t, "a", node)
+
+	health.recordProbe("a", time.Millisecond, 112)
+	assert.True(t, health.healthy("a"))
+	assert.True(t, health.healthy("b"))
+}
+
+func TestNodeHealth_PickPrefersBestScored(t *testing.T) {
+	health := newNodeHealth(5, 0, 0, 0)
+	health.setMaxTick(100)
+
+	nodes := []string{"slow", "fast1", "fast2", "fast3", "new"}
+	health.recordProbe("slow", time.Second, 100)
+	health.recordProbe("fast1", time.Millisecond, 100)
+	health.recordProbe("fast2", 2*time.Millisecond, 100)
+	health.recordProbe("fast3", 3*time.Millisecond, 100)
+
+	for i := 0; i < 50; i++ {
+		node, err := health.pick(nodes)
+		require.NoError(t, err)
+		assert.Contains(t, []string{"fast1", "fast2", "fast3"}, node)
+	}
+}
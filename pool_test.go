@@ -0,0 +1,106 @@
+package qubic
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNodeFetcher(t *testing.T, maxTick uint32, nodes ...*qubictest.Server) *httptest.Server {
+	resp := statusResponse{MaxTick: maxTick}
+	for _, node := range nodes {
+		resp.ReliableNodes = append(resp.ReliableNodes, nodeResponse{Address: node.Addr()})
+	}
+
+	fetcher := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(fetcher.Close)
+
+	return fetcher
+}
+
+func newTestNode(t *testing.T, tick uint32) *qubictest.Server {
+	server, err := qubictest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: tick})
+
+	return server
+}
+
+func TestPool_AvoidsLaggingNodes(t *testing.T) {
+	behind := newTestNode(t, 80)
+	synced := newTestNode(t, 100)
+	fetcher := newTestNodeFetcher(t, 100, behind, synced)
+
+	p, err := NewPoolConnection(PoolConfig{
+		MaxCap:             5,
+		MaxIdle:            5,
+		IdleTimeout:        time.Minute,
+		NodeFetcherUrl:     fetcher.URL,
+		NodeFetcherTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	// probe both nodes up front, otherwise the lagging node is only known if it is randomly picked first
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	p.factory.probeAll(ctx)
+
+	stats := p.NodeStats()
+	require.Len(t, stats, 2)
+	for _, stat := range stats {
+		if stat.Address == behind.Addr() {
+			assert.Equal(t, uint32(20), stat.Lag)
+		}
+	}
+
+	for i := 0; i < 5; i++ {
+		client, err := p.Get()
+		require.NoError(t, err)
+		assert.Equal(t, synced.Addr(), client.addr)
+		require.NoError(t, p.Close(client))
+	}
+}
+
+func TestPool_EvictsUnhealthyClients(t *testing.T) {
+	node := newTestNode(t, 100)
+	fetcher := newTestNodeFetcher(t, 100, node)
+
+	p, err := NewPoolConnection(PoolConfig{
+		MaxCap:              5,
+		MaxIdle:             5,
+		IdleTimeout:         time.Minute,
+		NodeFetcherUrl:      fetcher.URL,
+		NodeFetcherTimeout:  5 * time.Second,
+		HealthCheckInterval: 20 * time.Millisecond,
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	client, err := p.Get()
+	require.NoError(t, err)
+	require.NoError(t, p.Put(client))
+	assert.Equal(t, 1, p.chPool.Len())
+
+	// the node falls behind the max tick of the fetcher and the health check notices
+	node.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 50})
+	require.Eventually(t, func() bool {
+		stats := p.NodeStats()
+		return len(stats) == 1 && stats[0].Lag == 50
+	}, 2*time.Second, 10*time.Millisecond)
+
+	_, err = p.Get()
+	assert.Error(t, err)
+	assert.Equal(t, 0, p.chPool.Len())
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/binary"
 	"io"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/pkg/errors"
@@ -18,9 +19,23 @@ type ReaderUnmarshaler interface {
 
 var defaultTimeout = 5 * time.Second
 
+// Client is a connection to a single node. It is safe for concurrent use: a background reader routes every response
+// to the request with the same dejaVu, so many requests can be in flight on the same connection.
 type Client struct {
 	conn  net.Conn
+	addr  string
 	Peers types.PublicPeers
+
+	writeMu sync.Mutex
+
+	mu          sync.Mutex
+	pending     map[uint32]*packetQueue
+	readErr     error
+	closed      bool
+	readerDone  chan struct{}
+	peersPacket chan []byte
+
+	subscriptions map[*subscription]struct{}
 }
 
 func NewClient(ctx context.Context, nodeIP, nodePort string) (*Client, error) {
@@ -33,28 +48,60 @@ func NewClient(ctx context.Context, nodeIP, nodePort string) (*Client, error) {
 
 	conn, err := net.DialTimeout("tcp", net.JoinHostPort(nodeIP, nodePort), timeout)
 	if err != nil {
-		return nil, err
+		return nil, ErrNodeUnreachable{Addr: net.JoinHostPort(nodeIP, nodePort), Err: err}
 	}
 
-	c := Client{conn: conn}
+	c := newClient(conn)
+	c.addr = nodeIP
 
 	c.Peers, err = c.getPeers(ctx)
 	if err != nil {
+		c.Close()
 		return nil, errors.Wrap(err, "getting Peers")
 	}
 
-	return &c, nil
+	return c, nil
 }
 
 func NewClientWithConn(ctx context.Context, conn net.Conn) (*Client, error) {
-	return &Client{conn: conn}, nil
+	return newClient(conn), nil
+}
+
+func newClient(conn net.Conn) *Client {
+	c := Client{
+		conn:        conn,
+		pending:     make(map[uint32]*packetQueue),
+		readerDone:  make(chan struct{}),
+		peersPacket: make(chan []byte, 1),
+
+		subscriptions: make(map[*subscription]struct{}),
+	}
+
+	go c.readLoop()
+
+	return &c
 }
 
+// getPeers waits for the ExchangePublicPeers packet that the node sends as soon as the connection is established.
 func (qc *Client) getPeers(ctx context.Context) (types.PublicPeers, error) {
+	timer := time.NewTimer(time.Until(qc.responseDeadline(ctx)))
+	defer timer.Stop()
+
+	var packet []byte
+	select {
+	case packet = <-qc.peersPacket:
+	case <-qc.readerDone:
+		return types.PublicPeers{}, errors.Wrap(qc.readErr, "reading from node")
+	case <-ctx.Done():
+		return types.PublicPeers{}, ctx.Err()
+	case <-timer.C:
+		return types.PublicPeers{}, errors.Wrap(ErrTimeout, "waiting for public peers")
+	}
+
 	var result types.PublicPeers
-	err := qc.sendRequest(ctx, types.CurrentTickInfoRequest, nil, &result)
+	err := result.UnmarshallFromReader(bytes.NewReader(packet))
 	if err != nil {
-		return types.PublicPeers{}, errors.Wrap(err, "sending req to node")
+		return types.PublicPeers{}, errors.Wrap(err, "unmarshalling public peers")
 	}
 
 	return result, nil
@@ -163,6 +210,7 @@ func (qc *Client) GetTxStatus(ctx context.Context, tick uint32) (types.Transacti
 	return result, nil
 }
 
+// GetTickData returns the tick data of a past or current tick, or types.ErrEmptyTick when the node has none for it.
 func (qc *Client) GetTickData(ctx context.Context, tickNumber uint32) (types.TickData, error) {
 	tickInfo, err := qc.GetTickInfo(ctx)
 	if err != nil {
@@ -170,7 +218,7 @@ func (qc *Client) GetTickData(ctx context.Context, tickNumber uint32) (types.Tic
 	}
 
 	if tickInfo.Tick < tickNumber {
-		return types.TickData{}, errors.Errorf("Requested tick %d is in the future. Latest tick is: %d", tickNumber, tickInfo.Tick)
+		return types.TickData{}, ErrTickInFuture{Requested: tickNumber, Latest: tickInfo.Tick}
 	}
 
 	request := struct{ Tick uint32 }{Tick: tickNumber}
@@ -181,11 +229,18 @@ func (qc *Client) GetTickData(ctx context.Context, tickNumber uint32) (types.Tic
 		return types.TickData{}, errors.Wrap(err, "sending req to node")
 	}
 
+	if result.IsEmpty() {
+		return types.TickData{}, errors.Wrapf(types.ErrEmptyTick, "tick %d", tickNumber)
+	}
+
 	return result, nil
 }
 
 func (qc *Client) GetTickTransactions(ctx context.Context, tickNumber uint32) (types.Transactions, error) {
 	tickData, err := qc.GetTickData(ctx, tickNumber)
+	if errors.Is(err, types.ErrEmptyTick) {
+		return types.Transactions{}, nil
+	}
 	if err != nil {
 		return types.Transactions{}, errors.Wrap(err, "getting tick data")
 	}
@@ -253,7 +308,7 @@ func (qc *Client) GetQuorumVotes(ctx context.Context, tickNumber uint32) (types.
 	}
 
 	if tickInfo.Tick < tickNumber {
-		return types.QuorumVotes{}, errors.Errorf("Requested tick %d is in the future. Latest tick is: %d", tickNumber, tickInfo.Tick)
+		return types.QuorumVotes{}, ErrTickInFuture{Requested: tickNumber, Latest: tickInfo.Tick}
 	}
 
 	request := struct {
@@ -536,37 +591,61 @@ func (qc *Client) getAssetByUniverseIndex(ctx context.Context, index uint32, des
 
 }
 
+// Do sends a request of any type and decodes the response into dest. The payload is serialized little endian, as
+// raw bytes when it is a []byte. A nil dest doesn't wait for a response. Use *types.Packet as dest for a single raw
+// packet or *types.Packets for a response closed by EndResponse.
+func (qc *Client) Do(ctx context.Context, requestType uint8, payload interface{}, dest ReaderUnmarshaler) error {
+	return qc.sendRequest(ctx, requestType, payload, dest)
+}
+
 func (qc *Client) sendRequest(ctx context.Context, requestType uint8, requestData interface{}, dest ReaderUnmarshaler) error {
-	packet, err := serializeRequest(ctx, requestType, requestData)
-	if err != nil {
-		return errors.Wrapf(err, "serializing request for req type %d", requestType)
-	}
+	// broadcasts are sent with a zero dejaVu and never answered
+	if requestType == types.BroadcastTransaction {
+		packet, err := serializeRequest(ctx, requestType, 0, requestData)
+		if err != nil {
+			return errors.Wrapf(err, "serializing request for req type %d", requestType)
+		}
 
-	err = qc.writePacketToConn(ctx, packet)
-	if err != nil {
-		return errors.Wrapf(err, "sending packet to qubic conn for req type %d", requestType)
-	}
+		err = qc.writePacketToConn(ctx, packet)
+		if err != nil {
+			return errors.Wrapf(err, "sending packet to qubic conn for req type %d", requestType)
+		}
 
-	// if dest is nil then we don't care about the response
-	if dest == nil {
 		return nil
 	}
 
-	err = qc.readPacketIntoDest(ctx, dest)
+	dejaVu, queue, err := qc.registerRequest()
 	if err != nil {
-		return errors.Wrapf(err, "reading response for req type %d", requestType)
+		return errors.Wrapf(err, "registering req type %d", requestType)
 	}
+	defer qc.unregisterRequest(dejaVu)
 
-	return nil
+	packet, err := serializeRequest(ctx, requestType, dejaVu, requestData)
+	if err != nil {
+		return errors.Wrapf(err, "serializing request for req type %d", requestType)
+	}
+
+	return qc.exchange(ctx, requestType, packet, queue, dest)
 }
 
 func (qc *Client) sendSmartContractRequest(ctx context.Context, rcf RequestContractFunction, requestType uint8, requestData []byte, dest ReaderUnmarshaler) error {
-	packet, err := serializesSmartContractRequest(ctx, rcf, requestType, requestData)
+	dejaVu, queue, err := qc.registerRequest()
+	if err != nil {
+		return errors.Wrapf(err, "registering req type %d", requestType)
+	}
+	defer qc.unregisterRequest(dejaVu)
+
+	packet, err := serializesSmartContractRequest(ctx, rcf, requestType, dejaVu, requestData)
 	if err != nil {
 		return errors.Wrapf(err, "serializing request for req type %d", requestType)
 	}
 
-	err = qc.writePacketToConn(ctx, packet)
+	return qc.exchange(ctx, requestType, packet, queue, dest)
+}
+
+// exchange writes a request packet and decodes the packets routed back to it into dest.
+func (qc *Client) exchange(ctx context.Context, requestType uint8, packet []byte, queue *packetQueue, dest ReaderUnmarshaler) error {
+	err := qc.writePacketToConn(ctx, packet)
 	if err != nil {
 		return errors.Wrapf(err, "sending packet to qubic conn for req type %d", requestType)
 	}
@@ -576,7 +655,7 @@ func (qc *Client) sendSmartContractRequest(ctx context.Context, rcf RequestContr
 		return nil
 	}
 
-	err = qc.readPacketIntoDest(ctx, dest)
+	err = qc.readPacketIntoDest(ctx, queue, dest)
 	if err != nil {
 		return errors.Wrapf(err, "reading response for req type %d", requestType)
 	}
@@ -589,13 +668,10 @@ func (qc *Client) writePacketToConn(ctx context.Context, packet []byte) error {
 		return nil
 	}
 
-	// context deadline overrides defaultTimeout deadline
-	writeDeadline := time.Now().Add(defaultTimeout)
-	deadline, ok := ctx.Deadline()
-	if ok {
-		writeDeadline = deadline
-	}
-	err := qc.conn.SetWriteDeadline(writeDeadline)
+	qc.writeMu.Lock()
+	defer qc.writeMu.Unlock()
+
+	err := qc.conn.SetWriteDeadline(qc.responseDeadline(ctx))
 	if err != nil {
 		return errors.Wrap(err, "setting write deadline")
 	}
@@ -609,25 +685,13 @@ func (qc *Client) writePacketToConn(ctx context.Context, packet []byte) error {
 	return nil
 }
 
-func (qc *Client) readPacketIntoDest(ctx context.Context, dest ReaderUnmarshaler) error {
+func (qc *Client) readPacketIntoDest(ctx context.Context, queue *packetQueue, dest ReaderUnmarshaler) error {
 	if dest == nil {
 		return nil
 	}
 
-	// context deadline overrides defaultTimeout deadline
-	readDeadline := time.Now().Add(defaultTimeout)
-	deadline, ok := ctx.Deadline()
-	if ok {
-		readDeadline = deadline
-	}
-
-	err := qc.conn.SetReadDeadline(readDeadline)
-	if err != nil {
-		return errors.Wrap(err, "setting read deadline")
-	}
-	defer qc.conn.SetReadDeadline(time.Time{})
-
-	err = dest.UnmarshallFromReader(qc.conn)
+	reader := packetStreamReader{ctx: ctx, deadline: qc.responseDeadline(ctx), queue: queue, maxBytes: types.MaxResponseSize}
+	err := dest.UnmarshallFromReader(&reader)
 	if err != nil {
 		return errors.Wrap(err, "unmarshalling response")
 	}
@@ -635,9 +699,26 @@ func (qc *Client) readPacketIntoDest(ctx context.Context, dest ReaderUnmarshaler
 	return nil
 }
 
-// Close closes the connection
+// responseDeadline returns the context deadline, which overrides the defaultTimeout deadline
+func (qc *Client) responseDeadline(ctx context.Context) time.Time {
+	deadline, ok := ctx.Deadline()
+	if ok {
+		return deadline
+	}
+
+	return time.Now().Add(defaultTimeout)
+}
+
+// Close closes the connection and fails every request still waiting for a response
 func (qc *Client) Close() error {
-	return qc.conn.Close()
+	qc.mu.Lock()
+	qc.closed = true
+	qc.mu.Unlock()
+
+	err := qc.conn.Close()
+	<-qc.readerDone
+
+	return err
 }
 
 func serializeBinary(data interface{}) ([]byte, error) {
@@ -654,7 +735,7 @@ func serializeBinary(data interface{}) ([]byte, error) {
 	return buff.Bytes(), nil
 }
 
-func serializeRequest(ctx context.Context, requestType uint8, requestData interface{}) ([]byte, error) {
+func serializeRequest(ctx context.Context, requestType uint8, dejaVu uint32, requestData interface{}) ([]byte, error) {
 	serializedReqData, err := serializeBinary(requestData)
 	if err != nil {
 		return nil, errors.Wrap(err, "serializing req data")
@@ -667,11 +748,7 @@ func serializeRequest(ctx context.Context, requestType uint8, requestData interf
 	packetSize := uint32(packetHeaderSize + reqDataSize)
 
 	header.SetSize(packetSize)
-	if requestType == types.BroadcastTransaction {
-		header.ZeroDejaVu()
-	} else {
-		header.RandomizeDejaVu()
-	}
+	header.DejaVu = dejaVu
 
 	header.Type = requestType
 
@@ -693,7 +770,7 @@ type RequestContractFunction struct {
 	InputSize     uint16
 }
 
-func serializesSmartContractRequest(ctx context.Context, rcf RequestContractFunction, requestType uint8, requestData []byte) ([]byte, error) {
+func serializesSmartContractRequest(ctx context.Context, rcf RequestContractFunction, requestType uint8, dejaVu uint32, requestData []byte) ([]byte, error) {
 	serializedReqData := requestData
 	serializedReqContractFunction, err := serializeBinary(rcf)
 	if err != nil {
@@ -707,7 +784,7 @@ func serializesSmartContractRequest(ctx context.Context, rcf RequestContractFunc
 	reqContractFunctionSize := len(serializedReqContractFunction)
 	packetSize := uint32(packetHeaderSize + reqContractFunctionSize + reqDataSize)
 
-	header.RandomizeDejaVu()
+	header.DejaVu = dejaVu
 
 	header.Type = requestType
 	header.SetSize(packetSize)
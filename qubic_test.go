@@ -235,7 +235,7 @@ func Test_getTickTransactionsNrTx(t *testing.T) {
 		Month:         0,
 		Year:          0,
 		Timelock:      [32]byte{},
-		TransactionDigests: [1024][32]byte{
+		TransactionDigests: [1024]types.Digest{
 			{0x01, 0x02, 0x03, 0x04},
 			{0x05, 0x06, 0x07, 0x08},
 			{},
@@ -261,7 +261,7 @@ func Test_getTickTransactionsNrTx(t *testing.T) {
 		Month:         0,
 		Year:          0,
 		Timelock:      [32]byte{},
-		TransactionDigests: [1024][32]byte{
+		TransactionDigests: [1024]types.Digest{
 			{},
 			{},
 		},
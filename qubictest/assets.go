@@ -0,0 +1,178 @@
+package qubictest
+
+import (
+	"bytes"
+	"encoding/binary"
+
+	"github.com/qubic/go-node-connector/types"
+)
+
+// asset request types and filter flags, mirroring the ones used by the client
+const (
+	requestTypeAssetIssuanceRecords   uint16 = 0
+	requestTypeAssetOwnershipRecords  uint16 = 1
+	requestTypeAssetPossessionRecords uint16 = 2
+	requestTypeAssetByUniverseIndex   uint16 = 3
+
+	flagAnyIssuer            uint16 = 0b10
+	flagAnyAssetName         uint16 = 0b100
+	flagAnyOwner             uint16 = 0b1000
+	flagAnyOwnerContract     uint16 = 0b10000
+	flagAnyPossessor         uint16 = 0b100000
+	flagAnyPossessorContract uint16 = 0b1000000
+)
+
+type assetsByFilterRequest struct {
+	RequestType                uint16
+	Flags                      uint16
+	OwnershipManagingContract  uint16
+	PossessionManagingContract uint16
+	Issuer                     [32]byte
+	AssetName                  [8]byte
+	Owner                      [32]byte
+	Possessor                  [32]byte
+}
+
+type assetsByUniverseIndexRequest struct {
+	RequestType   uint16
+	Flags         uint16
+	UniverseIndex uint32
+}
+
+func (s *Server) handleAssets(w *connWriter, dejaVu uint32, payload []byte) {
+	if len(payload) < 2 {
+		return
+	}
+
+	var records [][]byte
+	switch binary.LittleEndian.Uint16(payload) {
+	case requestTypeAssetByUniverseIndex:
+		var request assetsByUniverseIndexRequest
+		err := binary.Read(bytes.NewReader(payload), binary.LittleEndian, &request)
+		if err != nil {
+			return
+		}
+		records = s.assetsByUniverseIndex(request.UniverseIndex)
+	case requestTypeAssetIssuanceRecords, requestTypeAssetOwnershipRecords, requestTypeAssetPossessionRecords:
+		var request assetsByFilterRequest
+		err := binary.Read(bytes.NewReader(payload), binary.LittleEndian, &request)
+		if err != nil {
+			return
+		}
+		records = s.assetsByFilter(request)
+	default:
+		return
+	}
+
+	for _, record := range records {
+		err := w.writePacket(types.RespondAssets, dejaVu, record)
+		if err != nil {
+			return
+		}
+	}
+
+	w.writePacket(types.EndResponse, dejaVu, nil)
+}
+
+func (s *Server) assetsByUniverseIndex(index uint32) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, issuance := range s.assetIssuances {
+		if issuance.UniverseIndex == index {
+			return [][]byte{assetRecord(issuance.Asset, issuance.Tick, issuance.UniverseIndex)}
+		}
+	}
+	for _, ownership := range s.assetOwnerships {
+		if ownership.UniverseIndex == index {
+			return [][]byte{assetRecord(ownership.Asset, ownership.Tick, ownership.UniverseIndex)}
+		}
+	}
+	for _, possession := range s.assetPossessions {
+		if possession.UniverseIndex == index {
+			return [][]byte{assetRecord(possession.Asset, possession.Tick, possession.UniverseIndex)}
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) assetsByFilter(request assetsByFilterRequest) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	issuances := make(map[uint32]bool)
+	var records [][]byte
+	for _, issuance := range s.assetIssuances {
+		if !matchesIssuance(request, issuance.Asset) {
+			continue
+		}
+		issuances[issuance.UniverseIndex] = true
+		if request.RequestType == requestTypeAssetIssuanceRecords {
+			records = append(records, assetRecord(issuance.Asset, issuance.Tick, issuance.UniverseIndex))
+		}
+	}
+	if request.RequestType == requestTypeAssetIssuanceRecords {
+		return records
+	}
+
+	ownerships := make(map[uint32]bool)
+	for _, ownership := range s.assetOwnerships {
+		if !issuances[ownership.Asset.IssuanceIndex] {
+			continue
+		}
+		if request.Flags&flagAnyOwner == 0 && ownership.Asset.PublicKey != request.Owner {
+			continue
+		}
+		if request.Flags&flagAnyOwnerContract == 0 && ownership.Asset.ManagingContractIndex != request.OwnershipManagingContract {
+			continue
+		}
+		ownerships[ownership.UniverseIndex] = true
+		if request.RequestType == requestTypeAssetOwnershipRecords {
+			records = append(records, assetRecord(ownership.Asset, ownership.Tick, ownership.UniverseIndex))
+		}
+	}
+	if request.RequestType == requestTypeAssetOwnershipRecords {
+		return records
+	}
+
+	for _, possession := range s.assetPossessions {
+		if !ownerships[possession.Asset.OwnershipIndex] {
+			continue
+		}
+		if request.Flags&flagAnyPossessor == 0 && possession.Asset.PublicKey != request.Possessor {
+			continue
+		}
+		if request.Flags&flagAnyPossessorContract == 0 && possession.Asset.ManagingContractIndex != request.PossessionManagingContract {
+			continue
+		}
+		records = append(records, assetRecord(possession.Asset, possession.Tick, possession.UniverseIndex))
+	}
+
+	return records
+}
+
+func matchesIssuance(request assetsByFilterRequest, issuance types.AssetIssuanceData) bool {
+	if request.Flags&flagAnyIssuer == 0 && issuance.PublicKey != request.Issuer {
+		return false
+	}
+
+	if request.Flags&flagAnyAssetName == 0 {
+		var name [8]byte
+		for i, c := range issuance.Name {
+			name[i] = byte(c)
+		}
+		if name != request.AssetName {
+			return false
+		}
+	}
+
+	return true
+}
+
+// assetRecord serializes a RespondAssets payload: the 48 byte asset record followed by its tick and universe index.
+func assetRecord(asset interface{}, tick, universeIndex uint32) []byte {
+	record := mustSerialize(asset)
+	record = append(record, mustSerialize(tick)...)
+	return append(record, mustSerialize(universeIndex)...)
+}
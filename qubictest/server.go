@@ -0,0 +1,581 @@
+// Package qubictest provides an in-process fake Qubic node for testing code built on top of the node connector
+// without network access.
+package qubictest
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// ContractFunction answers a smart contract function call with the raw output bytes.
+type ContractFunction func(input []byte) ([]byte, error)
+
+type contractFunctionKey struct {
+	contractIndex uint32
+	inputType     uint16
+}
+
+// Server is a fake node listening on a local TCP port. It speaks the RequestResponseHeader protocol and answers
+// requests from an in-memory state that tests program with the Set methods. Like a real node it sends an
+// ExchangePublicPeers packet as soon as a connection is accepted.
+type Server struct {
+	listener net.Listener
+
+	mu                sync.Mutex
+	peers             [4][4]byte
+	tickInfo          types.TickInfo
+	systemInfo        types.SystemInfo
+	identities        map[[32]byte]types.AddressInfo
+	tickData          map[uint32]types.TickData
+	tickTransactions  map[uint32]types.Transactions
+	quorumVotes       map[uint32]types.QuorumVotes
+	txStatuses        map[uint32]types.TransactionStatus
+	computors         types.Computors
+	assetIssuances    types.AssetIssuances
+	assetOwnerships   types.AssetOwnerships
+	assetPossessions  types.AssetPossessions
+	contractFunctions map[contractFunctionKey]ContractFunction
+	broadcasts        types.Transactions
+
+	conns  map[net.Conn]*connWriter
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// NewServer starts a fake node on a random loopback port.
+func NewServer() (*Server, error) {
+	return NewServerAt("127.0.0.1:0")
+}
+
+// NewServerAt starts a fake node on the given address. Nodes only advertise peer IPs, so tests with several nodes
+// that follow each other's peers run them on different loopback IPs sharing the same port.
+func NewServerAt(address string) (*Server, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, errors.Wrapf(err, "listening on %s", address)
+	}
+
+	s := Server{
+		listener:          listener,
+		identities:        make(map[[32]byte]types.AddressInfo),
+		tickData:          make(map[uint32]types.TickData),
+		tickTransactions:  make(map[uint32]types.Transactions),
+		quorumVotes:       make(map[uint32]types.QuorumVotes),
+		txStatuses:        make(map[uint32]types.TransactionStatus),
+		contractFunctions: make(map[contractFunctionKey]ContractFunction),
+		conns:             make(map[net.Conn]*connWriter),
+	}
+
+	s.wg.Add(1)
+	go s.acceptLoop()
+
+	return &s, nil
+}
+
+// Addr returns the host:port the server is listening on.
+func (s *Server) Addr() string {
+	return s.listener.Addr().String()
+}
+
+// Host returns the IP the server is listening on, ready to be passed to qubic.NewClient.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.Addr())
+	return host
+}
+
+// Port returns the port the server is listening on, ready to be passed to qubic.NewClient.
+func (s *Server) Port() string {
+	_, port, _ := net.SplitHostPort(s.Addr())
+	return port
+}
+
+// Close stops the listener, drops every open connection and waits for the handlers to return.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	err := s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+
+	s.wg.Wait()
+
+	return err
+}
+
+// SetPeers sets the IPv4 addresses advertised in the ExchangePublicPeers packet. At most 4 peers are sent.
+func (s *Server) SetPeers(peers []string) error {
+	if len(peers) > 4 {
+		return errors.Errorf("at most 4 peers can be advertised, got %d", len(peers))
+	}
+
+	var parsed [4][4]byte
+	for i, peer := range peers {
+		ip := net.ParseIP(peer).To4()
+		if ip == nil {
+			return errors.Errorf("invalid IPv4 peer address %s", peer)
+		}
+		copy(parsed[i][:], ip)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.peers = parsed
+
+	return nil
+}
+
+func (s *Server) SetTickInfo(tickInfo types.TickInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickInfo = tickInfo
+}
+
+func (s *Server) SetSystemInfo(systemInfo types.SystemInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.systemInfo = systemInfo
+}
+
+// SetIdentity stores the entity returned for AddressInfo.AddressData.PublicKey.
+func (s *Server) SetIdentity(addressInfo types.AddressInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.identities[addressInfo.AddressData.PublicKey] = addressInfo
+}
+
+// SetTickData stores the tick data returned for tickData.Tick.
+func (s *Server) SetTickData(tickData types.TickData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickData[tickData.Tick] = tickData
+}
+
+// SetTickTransactions stores the transactions of a tick. The position of a transaction in the slice is its slot in
+// TickData.TransactionDigests and is matched against the TransactionFlags of the request.
+func (s *Server) SetTickTransactions(tick uint32, txs types.Transactions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tickTransactions[tick] = txs
+}
+
+func (s *Server) SetQuorumVotes(tick uint32, votes types.QuorumVotes) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.quorumVotes[tick] = votes
+}
+
+// SetTxStatus stores the transaction status returned for txStatus.Tick. CurrentTickOfNode is filled in from the
+// tick info when the status is requested.
+func (s *Server) SetTxStatus(txStatus types.TransactionStatus) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.txStatuses[txStatus.Tick] = txStatus
+}
+
+func (s *Server) SetComputors(computors types.Computors) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.computors = computors
+}
+
+func (s *Server) SetAssetIssuances(issuances types.AssetIssuances) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assetIssuances = issuances
+}
+
+func (s *Server) SetAssetOwnerships(ownerships types.AssetOwnerships) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assetOwnerships = ownerships
+}
+
+func (s *Server) SetAssetPossessions(possessions types.AssetPossessions) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.assetPossessions = possessions
+}
+
+// SetContractFunction registers the handler answering ContractFunctionRequest for the given contract and input type.
+func (s *Server) SetContractFunction(contractIndex uint32, inputType uint16, fn ContractFunction) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.contractFunctions[contractFunctionKey{contractIndex: contractIndex, inputType: inputType}] = fn
+}
+
+// BroadcastTransactions returns the transactions clients broadcast to the server, in the order they were received.
+func (s *Server) BroadcastTransactions() types.Transactions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append(types.Transactions(nil), s.broadcasts...)
+}
+
+// Push sends an unsolicited packet with a zero dejaVu to every connected client, the way a node relays broadcasts.
+func (s *Server) Push(packetType uint8, payload []byte) error {
+	s.mu.Lock()
+	writers := make([]*connWriter, 0, len(s.conns))
+	for _, w := range s.conns {
+		writers = append(writers, w)
+	}
+	s.mu.Unlock()
+
+	for _, w := range writers {
+		err := w.writePacket(packetType, 0, payload)
+		if err != nil {
+			return errors.Wrap(err, "pushing packet")
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) acceptLoop() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			conn.Close()
+			return
+		}
+		w := connWriter{conn: conn}
+		s.conns[conn] = &w
+		s.wg.Add(1)
+		s.mu.Unlock()
+
+		go s.serveConn(&w)
+	}
+}
+
+func (s *Server) serveConn(w *connWriter) {
+	conn := w.conn
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	s.mu.Lock()
+	peers := s.peers
+	s.mu.Unlock()
+
+	err := w.writePacket(types.ExchangePublicPeers, 0, mustSerialize(peers))
+	if err != nil {
+		return
+	}
+
+	var handlers sync.WaitGroup
+	defer handlers.Wait()
+
+	for {
+		header, payload, err := readPacket(conn)
+		if err != nil {
+			return
+		}
+
+		// requests are answered concurrently, like a node does, so packets of different responses may interleave
+		handlers.Add(1)
+		go func() {
+			defer handlers.Done()
+			s.handle(w, header, payload)
+		}()
+	}
+}
+
+func (s *Server) handle(w *connWriter, header types.RequestResponseHeader, payload []byte) {
+	switch header.Type {
+	case types.CurrentTickInfoRequest:
+		s.handleTickInfo(w, header.DejaVu)
+	case types.SystemInfoRequest:
+		s.handleSystemInfo(w, header.DejaVu)
+	case types.BalanceTypeRequest:
+		s.handleBalance(w, header.DejaVu, payload)
+	case types.TickDataRequest:
+		s.handleTickData(w, header.DejaVu, payload)
+	case types.TickTransactionsRequest:
+		s.handleTickTransactions(w, header.DejaVu, payload)
+	case types.QuorumTickRequest:
+		s.handleQuorumTick(w, header.DejaVu, payload)
+	case types.TxStatusRequest:
+		s.handleTxStatus(w, header.DejaVu, payload)
+	case types.ComputorsRequest:
+		s.handleComputors(w, header.DejaVu)
+	case types.RequestAssets:
+		s.handleAssets(w, header.DejaVu, payload)
+	case types.ContractFunctionRequest:
+		s.handleContractFunction(w, header.DejaVu, payload)
+	case types.BroadcastTransaction:
+		s.handleBroadcastTransaction(payload)
+	}
+}
+
+func (s *Server) handleTickInfo(w *connWriter, dejaVu uint32) {
+	s.mu.Lock()
+	tickInfo := s.tickInfo
+	s.mu.Unlock()
+
+	w.writePacket(types.CurrentTickInfoResponse, dejaVu, mustSerialize(tickInfo))
+}
+
+func (s *Server) handleSystemInfo(w *connWriter, dejaVu uint32) {
+	s.mu.Lock()
+	systemInfo := s.systemInfo
+	s.mu.Unlock()
+
+	w.writePacket(types.SystemInfoResponse, dejaVu, mustSerialize(systemInfo))
+}
+
+func (s *Server) handleBalance(w *connWriter, dejaVu uint32, payload []byte) {
+	var pubKey [32]byte
+	if len(payload) < len(pubKey) {
+		return
+	}
+	copy(pubKey[:], payload)
+
+	s.mu.Lock()
+	addressInfo, ok := s.identities[pubKey]
+	tick := s.tickInfo.Tick
+	s.mu.Unlock()
+
+	// unknown entities are answered with an empty record, as a node does
+	if !ok {
+		addressInfo = types.AddressInfo{
+			AddressData:   types.AddressData{PublicKey: pubKey},
+			Tick:          tick,
+			SpectrumIndex: -1,
+		}
+	}
+
+	w.writePacket(types.BalanceTypeResponse, dejaVu, mustSerialize(addressInfo))
+}
+
+func (s *Server) handleTickData(w *connWriter, dejaVu uint32, payload []byte) {
+	if len(payload) < 4 {
+		return
+	}
+	tick := binary.LittleEndian.Uint32(payload)
+
+	s.mu.Lock()
+	tickData, ok := s.tickData[tick]
+	s.mu.Unlock()
+
+	if !ok || tickData.IsEmpty() {
+		w.writePacket(types.EndResponse, dejaVu, nil)
+		return
+	}
+
+	w.writePacket(types.BroadcastFutureTickData, dejaVu, mustSerialize(tickData))
+}
+
+func (s *Server) handleTickTransactions(w *connWriter, dejaVu uint32, payload []byte) {
+	var request struct {
+		Tick             uint32
+		TransactionFlags [types.NumberOfTransactionsPerTick / 8]uint8
+	}
+	err := binary.Read(bytes.NewReader(payload), binary.LittleEndian, &request)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	txs := s.tickTransactions[request.Tick]
+	s.mu.Unlock()
+
+	for i, tx := range txs {
+		if i >= types.NumberOfTransactionsPerTick {
+			break
+		}
+
+		// a set flag means the requester does not want that transaction
+		if request.TransactionFlags[i/8]&(1<<(i%8)) != 0 {
+			continue
+		}
+
+		serialized, err := tx.MarshallBinary()
+		if err != nil {
+			return
+		}
+
+		err = w.writePacket(types.BroadcastTransaction, dejaVu, serialized)
+		if err != nil {
+			return
+		}
+	}
+
+	w.writePacket(types.EndResponse, dejaVu, nil)
+}
+
+func (s *Server) handleQuorumTick(w *connWriter, dejaVu uint32, payload []byte) {
+	var request struct {
+		Tick      uint32
+		VoteFlags [(types.NumberOfComputors + 7) / 8]byte
+	}
+	err := binary.Read(bytes.NewReader(payload), binary.LittleEndian, &request)
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	votes := s.quorumVotes[request.Tick]
+	s.mu.Unlock()
+
+	for _, vote := range votes {
+		index := vote.ComputorIndex
+		if index < types.NumberOfComputors && request.VoteFlags[index/8]&(1<<(index%8)) != 0 {
+			continue
+		}
+
+		err = w.writePacket(types.QuorumTickResponse, dejaVu, mustSerialize(vote))
+		if err != nil {
+			return
+		}
+	}
+
+	w.writePacket(types.EndResponse, dejaVu, nil)
+}
+
+func (s *Server) handleTxStatus(w *connWriter, dejaVu uint32, payload []byte) {
+	if len(payload) < 4 {
+		return
+	}
+	tick := binary.LittleEndian.Uint32(payload)
+
+	s.mu.Lock()
+	txStatus, ok := s.txStatuses[tick]
+	currentTick := s.tickInfo.Tick
+	s.mu.Unlock()
+
+	// ticks without a stored status are answered as ticks without transactions
+	if !ok {
+		txStatus = types.TransactionStatus{Tick: tick}
+	}
+
+	response := struct {
+		CurrentTickOfNode uint32
+		Tick              uint32
+		TxCount           uint32
+		MoneyFlew         [(types.NumberOfTransactionsPerTick + 7) / 8]byte
+	}{
+		CurrentTickOfNode: currentTick,
+		Tick:              tick,
+		TxCount:           uint32(len(txStatus.TransactionDigests)),
+		MoneyFlew:         txStatus.MoneyFlew,
+	}
+
+	w.writePacket(types.TxStatusResponse, dejaVu, append(mustSerialize(response), mustSerialize(txStatus.TransactionDigests)...))
+}
+
+func (s *Server) handleComputors(w *connWriter, dejaVu uint32) {
+	s.mu.Lock()
+	computors := s.computors
+	s.mu.Unlock()
+
+	w.writePacket(types.BroadcastComputors, dejaVu, mustSerialize(computors))
+}
+
+func (s *Server) handleContractFunction(w *connWriter, dejaVu uint32, payload []byte) {
+	var request struct {
+		ContractIndex uint32
+		InputType     uint16
+		InputSize     uint16
+	}
+	reader := bytes.NewReader(payload)
+	err := binary.Read(reader, binary.LittleEndian, &request)
+	if err != nil {
+		return
+	}
+
+	input := make([]byte, reader.Len())
+	copy(input, payload[len(payload)-reader.Len():])
+
+	s.mu.Lock()
+	fn, ok := s.contractFunctions[contractFunctionKey{contractIndex: request.ContractIndex, inputType: request.InputType}]
+	s.mu.Unlock()
+
+	if !ok {
+		w.writePacket(types.EndResponse, dejaVu, nil)
+		return
+	}
+
+	output, err := fn(input)
+	if err != nil {
+		w.writePacket(types.EndResponse, dejaVu, nil)
+		return
+	}
+
+	w.writePacket(types.ContractFunctionResponse, dejaVu, output)
+}
+
+func (s *Server) handleBroadcastTransaction(payload []byte) {
+	var tx types.Transaction
+	err := tx.UnmarshallBinary(bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.broadcasts = append(s.broadcasts, tx)
+}
+
+type connWriter struct {
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// writePacket writes a whole packet at once so that concurrent responses never split each other's packets.
+func (w *connWriter) writePacket(packetType uint8, dejaVu uint32, payload []byte) error {
+	var header types.RequestResponseHeader
+	header.SetSize(uint32(binary.Size(header) + len(payload)))
+	header.Type = packetType
+	header.DejaVu = dejaVu
+
+	packet := append(mustSerialize(header), payload...)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	_, err := w.conn.Write(packet)
+	if err != nil {
+		return errors.Wrap(err, "writing packet")
+	}
+
+	return nil
+}
+
+func readPacket(r io.Reader) (types.RequestResponseHeader, []byte, error) {
+	packet, err := types.ReadPacket(r, types.MaxPacketSize)
+	if err != nil {
+		return types.RequestResponseHeader{}, nil, err
+	}
+
+	return packet.Header, packet.Payload, nil
+}
+
+// mustSerialize encodes fixed size protocol structs, which cannot fail.
+func mustSerialize(data interface{}) []byte {
+	var buff bytes.Buffer
+	err := binary.Write(&buff, binary.LittleEndian, data)
+	if err != nil {
+		panic(errors.Wrap(err, "serializing fixed size data"))
+	}
+
+	return buff.Bytes()
+}
@@ -0,0 +1,224 @@
+package qubictest_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	qubic "github.com/qubic/go-node-connector"
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testIdentity = "QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB"
+
+func newTestClient(t *testing.T) (*qubictest.Server, *qubic.Client) {
+	server, err := qubictest.NewServer()
+	require.NoError(t, err)
+	t.Cleanup(func() { server.Close() })
+
+	err = server.SetPeers([]string{"1.2.3.4", "5.6.7.8"})
+	require.NoError(t, err)
+
+	client, err := qubic.NewClient(context.Background(), server.Host(), server.Port())
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return server, client
+}
+
+func TestServer_ExchangePublicPeers(t *testing.T) {
+	_, client := newTestClient(t)
+
+	assert.Equal(t, types.PublicPeers{"1.2.3.4", "5.6.7.8"}, client.Peers)
+}
+
+func TestServer_GetTickInfo(t *testing.T) {
+	server, client := newTestClient(t)
+
+	expected := types.TickInfo{TickDuration: 2, Epoch: 150, Tick: 20200000, NumberOfAlignedVotes: 451, InitialTick: 20000000}
+	server.SetTickInfo(expected)
+
+	got, err := client.GetTickInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
+
+func TestServer_GetIdentity(t *testing.T) {
+	server, client := newTestClient(t)
+
+	id := types.Identity(testIdentity)
+	pubKey, err := id.ToPubKey(false)
+	require.NoError(t, err)
+
+	expected := types.AddressInfo{
+		AddressData: types.AddressData{
+			PublicKey:      pubKey,
+			IncomingAmount: 1000,
+			OutgoingAmount: 400,
+		},
+		Tick:          20200000,
+		SpectrumIndex: 42,
+		Siblings:      [types.SpectrumDepth][32]byte{{1}, {2}},
+	}
+	server.SetIdentity(expected)
+
+	got, err := client.GetIdentity(context.Background(), testIdentity)
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+
+	unknown, err := client.GetIdentity(context.Background(), "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAFXIB")
+	require.NoError(t, err)
+	assert.Equal(t, int32(-1), unknown.SpectrumIndex)
+}
+
+func TestServer_GetTickDataAndTransactions(t *testing.T) {
+	server, client := newTestClient(t)
+	server.SetTickInfo(types.TickInfo{Tick: 101})
+
+	txs := types.Transactions{
+		{SourcePublicKey: [32]byte{1}, Amount: 10, Tick: 100, Input: []byte{}, Signature: [64]byte{1}},
+		{SourcePublicKey: [32]byte{2}, Amount: 20, Tick: 100, InputType: 1, InputSize: 2, Input: []byte{1, 2}, Signature: [64]byte{2}},
+	}
+	tickData := types.TickData{Epoch: 150, Tick: 100, Signature: [64]byte{3}}
+	for i, tx := range txs {
+		digest, err := tx.Digest()
+		require.NoError(t, err)
+		tickData.TransactionDigests[i] = digest
+	}
+	server.SetTickData(tickData)
+	server.SetTickTransactions(100, txs)
+
+	gotTickData, err := client.GetTickData(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, tickData, gotTickData)
+
+	gotTxs, err := client.GetTickTransactions(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, txs, gotTxs)
+
+	_, err = client.GetTickData(context.Background(), 99)
+	assert.True(t, errors.Is(err, types.ErrEmptyTick))
+
+	_, err = client.GetTickData(context.Background(), 102)
+	assert.Error(t, err)
+}
+
+func TestServer_GetQuorumVotes(t *testing.T) {
+	server, client := newTestClient(t)
+	server.SetTickInfo(types.TickInfo{Tick: 101})
+
+	votes := types.QuorumVotes{
+		{ComputorIndex: 0, Epoch: 150, Tick: 100, TxDigest: [32]byte{1}},
+		{ComputorIndex: 1, Epoch: 150, Tick: 100, TxDigest: [32]byte{1}},
+	}
+	server.SetQuorumVotes(100, votes)
+
+	got, err := client.GetQuorumVotes(context.Background(), 100)
+	require.NoError(t, err)
+	assert.Equal(t, votes, got)
+}
+
+func TestServer_GetComputors(t *testing.T) {
+	server, client := newTestClient(t)
+
+	computors := types.Computors{Epoch: 150, PubKeys: [types.NumberOfComputors][32]byte{{1}, {2}}, Signature: [64]byte{3}}
+	server.SetComputors(computors)
+
+	got, err := client.GetComputors(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, computors, got)
+}
+
+func TestServer_GetAssets(t *testing.T) {
+	server, client := newTestClient(t)
+
+	owner := types.Identity(testIdentity)
+	ownerPubKey, err := owner.ToPubKey(false)
+	require.NoError(t, err)
+
+	server.SetAssetIssuances(types.AssetIssuances{
+		{Asset: types.AssetIssuanceData{Type: 1, Name: [7]int8{'Q', 'X'}}, Tick: 10, UniverseIndex: 3},
+		{Asset: types.AssetIssuanceData{Type: 1, Name: [7]int8{'R', 'A', 'N', 'D', 'O', 'M'}}, Tick: 11, UniverseIndex: 7},
+	})
+	server.SetAssetOwnerships(types.AssetOwnerships{
+		{Asset: types.AssetOwnershipData{PublicKey: ownerPubKey, Type: 2, ManagingContractIndex: 1, IssuanceIndex: 7, NumberOfUnits: 5}, Tick: 12, UniverseIndex: 100},
+		{Asset: types.AssetOwnershipData{PublicKey: [32]byte{9}, Type: 2, ManagingContractIndex: 1, IssuanceIndex: 3, NumberOfUnits: 1}, Tick: 12, UniverseIndex: 101},
+	})
+	server.SetAssetPossessions(types.AssetPossessions{
+		{Asset: types.AssetPossessionData{PublicKey: ownerPubKey, Type: 3, ManagingContractIndex: 1, OwnershipIndex: 100, NumberOfUnits: 5}, Tick: 13, UniverseIndex: 102},
+	})
+
+	issuances, err := client.GetAssetIssuancesByFilter(context.Background(), "", "RANDOM")
+	require.NoError(t, err)
+	require.Len(t, issuances, 1)
+	assert.Equal(t, uint32(7), issuances[0].UniverseIndex)
+
+	ownerships, err := client.GetAssetOwnershipsByFilter(context.Background(), "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAFXIB", "RANDOM", testIdentity, 1)
+	require.NoError(t, err)
+	require.Len(t, ownerships, 1)
+	assert.Equal(t, int64(5), ownerships[0].Asset.NumberOfUnits)
+
+	possessions, err := client.GetAssetPossessionsByFilter(context.Background(), "AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAFXIB", "RANDOM", "", testIdentity, 0, 0)
+	require.NoError(t, err)
+	require.Len(t, possessions, 1)
+	assert.Equal(t, uint32(102), possessions[0].UniverseIndex)
+
+	byIndex, err := client.GetAssetOwnershipsByUniverseIndex(context.Background(), 101)
+	require.NoError(t, err)
+	require.Len(t, byIndex, 1)
+	assert.Equal(t, [32]byte{9}, byIndex[0].Asset.PublicKey)
+
+	none, err := client.GetAssetIssuancesByUniverseIndex(context.Background(), 12345)
+	require.NoError(t, err)
+	assert.Empty(t, none)
+}
+
+func TestServer_QuerySmartContract(t *testing.T) {
+	server, client := newTestClient(t)
+
+	server.SetContractFunction(1, 1, func(input []byte) ([]byte, error) {
+		return append([]byte{0xAA}, input...), nil
+	})
+
+	rcf := qubic.RequestContractFunction{ContractIndex: 1, InputType: 1, InputSize: 2}
+	got, err := client.QuerySmartContract(context.Background(), rcf, []byte{1, 2})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0xAA, 1, 2}, got.Data)
+
+	rcf = qubic.RequestContractFunction{ContractIndex: 2, InputType: 1}
+	got, err = client.QuerySmartContract(context.Background(), rcf, nil)
+	require.NoError(t, err)
+	assert.Empty(t, got.Data)
+}
+
+func TestServer_GetTxStatus(t *testing.T) {
+	server, client := newTestClient(t)
+	server.SetTickInfo(types.TickInfo{Tick: 101})
+
+	txStatus := types.TransactionStatus{Tick: 100, TxCount: 2, MoneyFlew: [128]byte{0b10}, TransactionDigests: []types.Digest{{1}, {2}}}
+	server.SetTxStatus(txStatus)
+
+	got, err := client.GetTxStatus(context.Background(), 100)
+	require.NoError(t, err)
+	txStatus.CurrentTickOfNode = 101
+	assert.Equal(t, txStatus, got)
+
+	empty, err := client.GetTxStatus(context.Background(), 99)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(99), empty.Tick)
+	assert.Empty(t, empty.TransactionDigests)
+}
+
+func TestServer_GetSystemInfo(t *testing.T) {
+	server, client := newTestClient(t)
+
+	expected := types.SystemInfo{Version: 231, Epoch: 150, Tick: 20200000, InitialTick: 20000000}
+	server.SetSystemInfo(expected)
+
+	got, err := client.GetSystemInfo(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, expected, got)
+}
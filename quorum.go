@@ -0,0 +1,172 @@
+package qubic
+
+import (
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// QuorumDigests are the digests agreed on by the aligned votes of a tick. Spectrum, Universe and Computer are the
+// Previous* digests of the votes, the state before the tick is processed, while Tx is the digest of the transactions
+// of the tick itself.
+type QuorumDigests struct {
+	Spectrum types.Digest
+	Universe types.Digest
+	Computer types.Digest
+	Tx       types.Digest
+}
+
+type QuorumResult struct {
+	Tick  uint32
+	Epoch uint16
+	// Reached is true when at least types.MinimumQuorumVotes aligned votes agree on the digests.
+	Reached      bool
+	AlignedVotes int
+	Digests      QuorumDigests
+	// DissentingComputors are the indices of computors with a valid vote that disagrees with the aligned votes.
+	DissentingComputors []uint16
+	// InvalidComputors are the indices of computors whose vote has a bad signature or was sent more than once.
+	InvalidComputors []uint16
+	// FaultyComputors are the indices of computors that signed conflicting votes for the tick. None of their votes
+	// count.
+	FaultyComputors []uint16
+}
+
+// voteKey holds the unsalted fields that aligned votes of a tick share. Salted digests differ per computor.
+type voteKey struct {
+	Epoch       uint16
+	Tick        uint32
+	Millisecond uint16
+	Second      uint8
+	Minute      uint8
+	Hour        uint8
+	Day         uint8
+	Month       uint8
+	Year        uint8
+
+	PreviousResourceTestingDigest uint32
+	PreviousTransactionBodyDigest uint32
+
+	PreviousSpectrumDigest   types.Digest
+	PreviousUniverseDigest   types.Digest
+	PreviousComputerDigest   types.Digest
+	TxDigest                 types.Digest
+	ExpectedNextTickTxDigest types.Digest
+}
+
+func newVoteKey(vote types.QuorumTickVote) voteKey {
+	return voteKey{
+		Epoch:                         vote.Epoch,
+		Tick:                          vote.Tick,
+		Millisecond:                   vote.Millisecond,
+		Second:                        vote.Second,
+		Minute:                        vote.Minute,
+		Hour:                          vote.Hour,
+		Day:                           vote.Day,
+		Month:                         vote.Month,
+		Year:                          vote.Year,
+		PreviousResourceTestingDigest: vote.PreviousResourceTestingDigest,
+		PreviousTransactionBodyDigest: vote.PreviousTransactionBodyDigest,
+		PreviousSpectrumDigest:        vote.PreviousSpectrumDigest,
+		PreviousUniverseDigest:        vote.PreviousUniverseDigest,
+		PreviousComputerDigest:        vote.PreviousComputerDigest,
+		TxDigest:                      vote.TxDigest,
+		ExpectedNextTickTxDigest:      vote.ExpectedNextTickTxDigest,
+	}
+}
+
+// CheckQuorum verifies the signatures of the votes of a single tick against the computor list of the epoch, groups
+// the valid votes by the digests they vote for and reports whether the largest group reaches the quorum. The digests
+// of the result are those of the aligned votes, see QuorumDigests. A computor that signed conflicting votes is faulty,
+// none of its votes count.
+func CheckQuorum(votes types.QuorumVotes, computors types.Computors) (QuorumResult, error) {
+	if len(votes) == 0 {
+		return QuorumResult{}, errors.New("no quorum votes")
+	}
+
+	tick := votes[0].Tick
+	for _, vote := range votes {
+		if vote.Tick != tick {
+			return QuorumResult{}, errors.Errorf("votes for different ticks, found %d and %d", tick, vote.Tick)
+		}
+	}
+
+	result := QuorumResult{Tick: tick, Epoch: computors.Epoch}
+
+	// the first valid vote of every computor, to tell a resent vote from a conflicting one
+	first := make(map[uint16]types.QuorumTickVote, len(votes))
+	faulty := make(map[uint16]bool)
+	var valid []types.QuorumTickVote
+	for _, vote := range votes {
+		err := vote.Verify(computors)
+		if err != nil {
+			result.InvalidComputors = append(result.InvalidComputors, vote.ComputorIndex)
+			continue
+		}
+
+		previous, ok := first[vote.ComputorIndex]
+		if !ok {
+			first[vote.ComputorIndex] = vote
+			valid = append(valid, vote)
+			continue
+		}
+
+		if unsigned(previous) == unsigned(vote) {
+			result.InvalidComputors = append(result.InvalidComputors, vote.ComputorIndex)
+			continue
+		}
+
+		if !faulty[vote.ComputorIndex] {
+			faulty[vote.ComputorIndex] = true
+			result.FaultyComputors = append(result.FaultyComputors, vote.ComputorIndex)
+		}
+	}
+
+	groups := make(map[voteKey][]uint16)
+	var order []voteKey
+	for _, vote := range valid {
+		if faulty[vote.ComputorIndex] {
+			continue
+		}
+
+		key := newVoteKey(vote)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], vote.ComputorIndex)
+	}
+
+	if len(order) == 0 {
+		return result, nil
+	}
+
+	aligned := order[0]
+	for _, key := range order[1:] {
+		if len(groups[key]) > len(groups[aligned]) {
+			aligned = key
+		}
+	}
+
+	for _, key := range order {
+		if key != aligned {
+			result.DissentingComputors = append(result.DissentingComputors, groups[key]...)
+		}
+	}
+
+	result.AlignedVotes = len(groups[aligned])
+	result.Reached = result.AlignedVotes >= types.MinimumQuorumVotes
+	result.Digests = QuorumDigests{
+		Spectrum: aligned.PreviousSpectrumDigest,
+		Universe: aligned.PreviousUniverseDigest,
+		Computer: aligned.PreviousComputerDigest,
+		Tx:       aligned.TxDigest,
+	}
+
+	return result, nil
+}
+
+// unsigned returns the vote without its signature, signatures of the same vote may differ.
+func unsigned(vote types.QuorumTickVote) types.QuorumTickVote {
+	vote.Signature = [types.SignatureSize]byte{}
+
+	return vote
+}
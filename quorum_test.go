@@ -0,0 +1,122 @@
+package qubic
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/qubic/go-node-connector/types"
+	"github.com/qubic/go-schnorrq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testComputor struct {
+	subSeed [32]byte
+	pubKey  [32]byte
+}
+
+// newTestQuorum creates the given number of computors with deterministic seeds. The computor list is not signed.
+func newTestQuorum(t *testing.T, count int) (types.Computors, []testComputor) {
+	computors := types.Computors{Epoch: 150}
+	keys := make([]testComputor, count)
+	for i := 0; i < count; i++ {
+		seed := []byte(strings.Repeat("a", 55))
+		for j, n := 0, i; n > 0; j, n = j+1, n/26 {
+			seed[j] = byte('a' + n%26)
+		}
+
+		wallet, err := types.NewWallet(string(seed))
+		require.NoError(t, err)
+		subSeed, err := types.GetSubSeed(string(seed))
+		require.NoError(t, err)
+
+		keys[i] = testComputor{subSeed: subSeed, pubKey: wallet.PubKey}
+		computors.PubKeys[i] = wallet.PubKey
+	}
+
+	return computors, keys
+}
+
+func signVote(t *testing.T, vote types.QuorumTickVote, computor testComputor) types.QuorumTickVote {
+	digest, err := vote.GetDigest()
+	require.NoError(t, err)
+
+	vote.Signature, err = schnorrq.Sign(computor.subSeed, computor.pubKey, digest)
+	require.NoError(t, err)
+
+	return vote
+}
+
+func TestCheckQuorum(t *testing.T) {
+	computors, keys := newTestQuorum(t, types.MinimumQuorumVotes+4)
+
+	aligned := types.QuorumTickVote{
+		Epoch:                  150,
+		Tick:                   20200000,
+		Second:                 30,
+		PreviousSpectrumDigest: [32]byte{1},
+		PreviousUniverseDigest: [32]byte{2},
+		PreviousComputerDigest: [32]byte{3},
+		TxDigest:               [32]byte{4},
+	}
+
+	var votes types.QuorumVotes
+	for i := 0; i < types.MinimumQuorumVotes; i++ {
+		vote := aligned
+		vote.ComputorIndex = uint16(i)
+		// salted digests differ per computor and must not split the aligned votes
+		vote.SaltedSpectrumDigest = [32]byte{byte(i)}
+		votes = append(votes, signVote(t, vote, keys[i]))
+	}
+
+	dissenting := aligned
+	dissenting.ComputorIndex = types.MinimumQuorumVotes
+	dissenting.TxDigest = [32]byte{5}
+	votes = append(votes, signVote(t, dissenting, keys[types.MinimumQuorumVotes]))
+
+	badSignature := aligned
+	badSignature.ComputorIndex = types.MinimumQuorumVotes + 1
+	badSignature = signVote(t, badSignature, keys[0])
+	votes = append(votes, badSignature)
+
+	duplicate := votes[0]
+	votes = append(votes, duplicate)
+
+	// a computor signing two conflicting votes is faulty, even when one of them is aligned
+	equivocating := aligned
+	equivocating.ComputorIndex = types.MinimumQuorumVotes + 2
+	votes = append(votes, signVote(t, equivocating, keys[types.MinimumQuorumVotes+2]))
+	equivocating.PreviousTransactionBodyDigest = 7
+	votes = append(votes, signVote(t, equivocating, keys[types.MinimumQuorumVotes+2]))
+
+	// votes that only disagree on the expected transactions of the next tick are not aligned either
+	nextTickDissenting := aligned
+	nextTickDissenting.ComputorIndex = types.MinimumQuorumVotes + 3
+	nextTickDissenting.ExpectedNextTickTxDigest = [32]byte{6}
+	votes = append(votes, signVote(t, nextTickDissenting, keys[types.MinimumQuorumVotes+3]))
+
+	result, err := CheckQuorum(votes, computors)
+	require.NoError(t, err)
+
+	assert.True(t, result.Reached)
+	assert.Equal(t, uint32(20200000), result.Tick)
+	assert.Equal(t, types.MinimumQuorumVotes, result.AlignedVotes)
+	assert.Equal(t, QuorumDigests{Spectrum: [32]byte{1}, Universe: [32]byte{2}, Computer: [32]byte{3}, Tx: [32]byte{4}}, result.Digests)
+	assert.Equal(t, []uint16{types.MinimumQuorumVotes, types.MinimumQuorumVotes + 3}, result.DissentingComputors)
+	assert.Equal(t, []uint16{types.MinimumQuorumVotes + 1, 0}, result.InvalidComputors)
+	assert.Equal(t, []uint16{types.MinimumQuorumVotes + 2}, result.FaultyComputors)
+
+	// one aligned vote less is not enough
+	result, err = CheckQuorum(votes[1:types.MinimumQuorumVotes], computors)
+	require.NoError(t, err)
+	assert.False(t, result.Reached)
+	assert.Equal(t, types.MinimumQuorumVotes-1, result.AlignedVotes)
+}
+
+func TestCheckQuorum_InvalidInput(t *testing.T) {
+	_, err := CheckQuorum(nil, types.Computors{})
+	assert.Error(t, err)
+
+	_, err = CheckQuorum(types.QuorumVotes{{Tick: 1}, {Tick: 2}}, types.Computors{})
+	assert.Error(t, err)
+}
@@ -0,0 +1,113 @@
+package qubic
+
+import (
+	"context"
+	"encoding/binary"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// subscriptionBufferSize is the number of packets buffered per subscription. Packets are dropped while the buffer is
+// full, so a slow subscriber cannot stall the responses of the other requests.
+const subscriptionBufferSize = 256
+
+// SubscriptionFilter selects the unsolicited packets a subscription receives.
+type SubscriptionFilter struct {
+	// Types are the packet types to receive, types.BroadcastTypes when empty.
+	Types []uint8
+	// Raw receives the packets without decoding them, types.Packet.Data is nil.
+	Raw bool
+}
+
+type subscription struct {
+	types   map[uint8]bool
+	raw     bool
+	packets chan types.Packet
+}
+
+// Subscribe streams the packets the node broadcasts without being asked, decoded into their typed structs in
+// types.Packet.Data unless the filter asks for raw packets. A packet that fails to decode is delivered with nil Data
+// and the error in types.Packet.DecodeErr. The channel is closed when ctx is done or the connection is
+// closed.
+func (qc *Client) Subscribe(ctx context.Context, filter SubscriptionFilter) (<-chan types.Packet, error) {
+	packetTypes := filter.Types
+	if len(packetTypes) == 0 {
+		packetTypes = types.BroadcastTypes
+	}
+
+	sub := subscription{
+		types:   make(map[uint8]bool, len(packetTypes)),
+		raw:     filter.Raw,
+		packets: make(chan types.Packet, subscriptionBufferSize),
+	}
+	for _, packetType := range packetTypes {
+		sub.types[packetType] = true
+	}
+
+	qc.mu.Lock()
+	if qc.readErr != nil {
+		qc.mu.Unlock()
+		return nil, errors.Wrap(qc.readErr, "connection is not readable")
+	}
+	qc.subscriptions[&sub] = struct{}{}
+	qc.mu.Unlock()
+
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-qc.readerDone:
+		}
+		qc.unsubscribe(&sub)
+	}()
+
+	return sub.packets, nil
+}
+
+func (qc *Client) unsubscribe(sub *subscription) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	if _, ok := qc.subscriptions[sub]; !ok {
+		return
+	}
+
+	delete(qc.subscriptions, sub)
+	close(sub.packets)
+}
+
+// publish sends an unsolicited packet to the subscriptions that want its type. The packet is decoded once, for the
+// subscriptions that are not raw.
+func (qc *Client) publish(header types.RequestResponseHeader, packet []byte) {
+	qc.mu.Lock()
+	defer qc.mu.Unlock()
+
+	raw := types.Packet{Header: header, Payload: packet[binary.Size(header):]}
+	var decoded *types.Packet
+	for sub := range qc.subscriptions {
+		if !sub.types[header.Type] {
+			continue
+		}
+
+		if sub.raw {
+			sub.send(raw)
+			continue
+		}
+
+		if decoded == nil {
+			p := raw
+			p.Data, p.DecodeErr = types.DecodePacket(p)
+			decoded = &p
+		}
+
+		sub.send(*decoded)
+	}
+}
+
+// send delivers the packet unless the buffer of the subscription is full.
+func (sub *subscription) send(packet types.Packet) {
+	select {
+	case sub.packets <- packet:
+	default:
+	}
+}
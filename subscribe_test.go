@@ -0,0 +1,121 @@
+package qubic
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func receivePacket(t *testing.T, packets <-chan types.Packet) types.Packet {
+	select {
+	case packet, ok := <-packets:
+		require.True(t, ok, "subscription closed")
+		return packet
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "no packet received")
+	}
+
+	return types.Packet{}
+}
+
+func TestClient_Subscribe(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	txs, err := client.Subscribe(ctx, SubscriptionFilter{Types: []uint8{types.BroadcastTransaction}})
+	require.NoError(t, err)
+	all, err := client.Subscribe(ctx, SubscriptionFilter{})
+	require.NoError(t, err)
+
+	tickData := types.TickData{Epoch: 150, Tick: 1000, Signature: [64]byte{1}}
+	var buff bytes.Buffer
+	require.NoError(t, binary.Write(&buff, binary.LittleEndian, tickData))
+	require.NoError(t, server.Push(types.BroadcastFutureTickData, buff.Bytes()))
+
+	tx := types.Transaction{Amount: 10, Tick: 1001, InputSize: 2, Input: []byte{1, 2}, Signature: [64]byte{2}}
+	payload, err := tx.MarshallBinary()
+	require.NoError(t, err)
+	require.NoError(t, server.Push(types.BroadcastTransaction, payload))
+
+	packet := receivePacket(t, txs)
+	assert.Equal(t, uint8(types.BroadcastTransaction), packet.Header.Type)
+	assert.Equal(t, tx, packet.Data)
+
+	packet = receivePacket(t, all)
+	assert.Equal(t, tickData, packet.Data)
+	packet = receivePacket(t, all)
+	assert.Equal(t, tx, packet.Data)
+
+	// requests keep working while subscribed
+	server.SetTickInfo(types.TickInfo{Tick: 1000})
+	_, err = client.GetTickInfo(context.Background())
+	require.NoError(t, err)
+
+	cancel()
+	select {
+	case _, ok := <-txs:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "subscription not closed")
+	}
+}
+
+func TestClient_SubscribeRaw(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	raw, err := client.Subscribe(ctx, SubscriptionFilter{Types: []uint8{types.BroadcastTransaction}, Raw: true})
+	require.NoError(t, err)
+	decoded, err := client.Subscribe(ctx, SubscriptionFilter{Types: []uint8{types.BroadcastTransaction}})
+	require.NoError(t, err)
+
+	// a transaction too short to decode reaches the decoded subscription with its error
+	require.NoError(t, server.Push(types.BroadcastTransaction, []byte{1, 2, 3}))
+
+	tx := types.Transaction{Amount: 10, Tick: 1001, Input: []byte{}, Signature: [64]byte{2}}
+	payload, err := tx.MarshallBinary()
+	require.NoError(t, err)
+	require.NoError(t, server.Push(types.BroadcastTransaction, payload))
+
+	packet := receivePacket(t, raw)
+	assert.Equal(t, []byte{1, 2, 3}, packet.Payload)
+	assert.Nil(t, packet.Data)
+	packet = receivePacket(t, raw)
+	assert.Equal(t, payload, packet.Payload)
+	assert.Nil(t, packet.Data)
+
+	packet = receivePacket(t, decoded)
+	assert.Nil(t, packet.Data)
+	assert.Error(t, packet.DecodeErr)
+	packet = receivePacket(t, decoded)
+	assert.Equal(t, tx, packet.Data)
+	assert.NoError(t, packet.DecodeErr)
+}
+
+func TestClient_SubscribeClosedClient(t *testing.T) {
+	_, client := newFakeNodeClient(t)
+
+	packets, err := client.Subscribe(context.Background(), SubscriptionFilter{})
+	require.NoError(t, err)
+
+	require.NoError(t, client.Close())
+	select {
+	case _, ok := <-packets:
+		assert.False(t, ok)
+	case <-time.After(2 * time.Second):
+		assert.Fail(t, "subscription not closed")
+	}
+
+	_, err = client.Subscribe(context.Background(), SubscriptionFilter{})
+	assert.Error(t, err)
+}
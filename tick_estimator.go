@@ -0,0 +1,195 @@
+package qubic
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+const (
+	defaultEstimatorWindow          = 10
+	defaultEstimatorPropagationTime = 5 * time.Second
+)
+
+type TickEstimatorConfig struct {
+	// Window is the number of recent ticks whose timestamps are sampled, 10 when zero.
+	Window int
+	// PropagationTime is the time a transaction needs to be sent and spread over the network before its target tick
+	// starts, 5 seconds when zero.
+	PropagationTime time.Duration
+}
+
+// TickEstimate is the tick pace observed by the last TickEstimator.Update.
+type TickEstimate struct {
+	CurrentTick uint32
+	// ObservedAt is the time CurrentTick was read from the node.
+	ObservedAt time.Time
+	// TickDuration is the mean time between two ticks.
+	TickDuration time.Duration
+	// Jitter is the standard deviation of the time between two ticks.
+	Jitter time.Duration
+	// AnchorTick and AnchorTime are the most recent tick with a timestamp, used to predict when later ticks start.
+	AnchorTick uint32
+	AnchorTime time.Time
+}
+
+// TickEstimator picks target ticks for transactions and predicts when future ticks start, from the pace of the recent
+// ticks.
+type TickEstimator struct {
+	fetcher TickFetcher
+	config  TickEstimatorConfig
+	now     func() time.Time
+
+	mu       sync.Mutex
+	estimate *TickEstimate
+}
+
+func NewTickEstimator(fetcher TickFetcher, config TickEstimatorConfig) *TickEstimator {
+	if config.Window < 2 {
+		config.Window = defaultEstimatorWindow
+	}
+	if config.PropagationTime <= 0 {
+		config.PropagationTime = defaultEstimatorPropagationTime
+	}
+
+	return &TickEstimator{fetcher: fetcher, config: config, now: time.Now}
+}
+
+// Update reads the current tick and the timestamps of the ticks before it. When fewer than two recent ticks carry a
+// timestamp, the duration of the last tick reported in TickInfo.TickDuration is used instead, with no jitter.
+func (te *TickEstimator) Update(ctx context.Context) (TickEstimate, error) {
+	tickInfo, err := te.fetcher.GetTickInfo(ctx)
+	if err != nil {
+		return TickEstimate{}, errors.Wrap(err, "getting tick info")
+	}
+
+	estimate := TickEstimate{
+		CurrentTick:  tickInfo.Tick,
+		ObservedAt:   te.now(),
+		TickDuration: time.Duration(tickInfo.TickDuration) * time.Millisecond,
+	}
+
+	first := tickInfo.InitialTick
+	if tickInfo.Tick > first+uint32(te.config.Window) {
+		first = tickInfo.Tick - uint32(te.config.Window)
+	}
+
+	type sample struct {
+		tick uint32
+		time time.Time
+	}
+	var samples []sample
+	for tick := first; tick < tickInfo.Tick; tick++ {
+		tickData, err := te.fetcher.GetTickData(ctx, tick)
+		// empty ticks carry no timestamp
+		if errors.Is(err, types.ErrEmptyTick) {
+			continue
+		}
+		if err != nil {
+			return TickEstimate{}, errors.Wrapf(err, "getting tick data %d", tick)
+		}
+		samples = append(samples, sample{tick: tick, time: tickData.Timestamp()})
+	}
+
+	if len(samples) > 0 {
+		last := samples[len(samples)-1]
+		estimate.AnchorTick, estimate.AnchorTime = last.tick, last.time
+	}
+
+	if len(samples) >= 2 {
+		firstSample, lastSample := samples[0], samples[len(samples)-1]
+		mean := float64(lastSample.time.Sub(firstSample.time)) / float64(lastSample.tick-firstSample.tick)
+
+		var variance float64
+		for i := 1; i < len(samples); i++ {
+			perTick := float64(samples[i].time.Sub(samples[i-1].time)) / float64(samples[i].tick-samples[i-1].tick)
+			variance += (perTick - mean) * (perTick - mean)
+		}
+		variance /= float64(len(samples) - 1)
+
+		estimate.TickDuration = time.Duration(mean)
+		estimate.Jitter = time.Duration(math.Sqrt(variance))
+	}
+
+	if estimate.TickDuration <= 0 {
+		return TickEstimate{}, errors.New("no tick duration available")
+	}
+
+	te.mu.Lock()
+	te.estimate = &estimate
+	te.mu.Unlock()
+
+	return estimate, nil
+}
+
+// anchor returns the tick predictions are counted from, the current tick when no recent tick had a timestamp.
+func (e TickEstimate) anchor() (uint32, time.Time) {
+	if e.AnchorTime.IsZero() {
+		return e.CurrentTick, e.ObservedAt
+	}
+
+	return e.AnchorTick, e.AnchorTime
+}
+
+func (te *TickEstimator) lastEstimate() (TickEstimate, error) {
+	te.mu.Lock()
+	defer te.mu.Unlock()
+
+	if te.estimate == nil {
+		return TickEstimate{}, errors.New("no estimate, Update was not called")
+	}
+
+	return *te.estimate, nil
+}
+
+// SuggestTargetTick returns the closest tick that, with the given confidence between 0 and 1, starts after the
+// transaction had PropagationTime to spread. Closer ticks risk being missed, later ones make the transaction wait for
+// nothing. Tick durations are assumed normally distributed. Like PredictTime, ticks are counted from the anchor tick,
+// so the time already spent in the current tick is accounted for.
+func (te *TickEstimator) SuggestTargetTick(confidence float64) (uint32, error) {
+	if confidence <= 0 || confidence >= 1 {
+		return 0, errors.Errorf("confidence must be between 0 and 1, got %f", confidence)
+	}
+
+	estimate, err := te.lastEstimate()
+	if err != nil {
+		return 0, err
+	}
+
+	// time from the start of the anchor tick until the transaction spread
+	anchorTick, anchorTime := estimate.anchor()
+	needed := float64(te.now().Add(te.config.PropagationTime).Sub(anchorTime))
+	if needed < 0 {
+		needed = 0
+	}
+	mean := float64(estimate.TickDuration)
+	stdDev := float64(estimate.Jitter)
+	z := math.Sqrt2 * math.Erfinv(2*confidence-1)
+
+	// smallest k with k*mean - z*sqrt(k)*stdDev >= needed, solved for x = sqrt(k)
+	x := (z*stdDev + math.Sqrt(z*z*stdDev*stdDev+4*mean*needed)) / (2 * mean)
+	// the epsilon keeps rounding errors from adding a tick when k is a whole number
+	target := uint64(anchorTick) + uint64(math.Ceil(x*x-1e-9))
+	if target <= uint64(estimate.CurrentTick) {
+		target = uint64(estimate.CurrentTick) + 1
+	}
+
+	return uint32(target), nil
+}
+
+// PredictTime returns when the tick is expected to start.
+func (te *TickEstimator) PredictTime(tick uint32) (time.Time, error) {
+	estimate, err := te.lastEstimate()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	anchorTick, anchorTime := estimate.anchor()
+	ticks := int64(tick) - int64(anchorTick)
+
+	return anchorTime.Add(time.Duration(ticks) * estimate.TickDuration), nil
+}
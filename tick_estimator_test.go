@@ -0,0 +1,147 @@
+package qubic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setTickTimestamp(server *qubictest.Server, tick uint32, timestamp time.Time) {
+	server.SetTickData(types.TickData{
+		Epoch:       150,
+		Tick:        tick,
+		Millisecond: uint16(timestamp.Nanosecond() / int(time.Millisecond)),
+		Second:      uint8(timestamp.Second()),
+		Minute:      uint8(timestamp.Minute()),
+		Hour:        uint8(timestamp.Hour()),
+		Day:         uint8(timestamp.Day()),
+		Month:       uint8(timestamp.Month()),
+		Year:        uint8(timestamp.Year() - 2000),
+	})
+}
+
+func TestTickEstimator_RegularTicks(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	start := time.Date(2024, time.July, 3, 14, 30, 0, 0, time.UTC)
+	for tick := uint32(100); tick < 110; tick++ {
+		// tick 105 is empty and has no timestamp
+		if tick != 105 {
+			setTickTimestamp(server, tick, start.Add(time.Duration(tick-100)*2*time.Second))
+		}
+	}
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 110, InitialTick: 100, TickDuration: 3000})
+
+	estimator := NewTickEstimator(client, TickEstimatorConfig{PropagationTime: 5 * time.Second})
+	// tick 110 just started
+	now := start.Add(20 * time.Second)
+	estimator.now = func() time.Time { return now }
+
+	estimate, err := estimator.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, estimate.TickDuration)
+	assert.Equal(t, time.Duration(0), estimate.Jitter)
+	assert.Equal(t, uint32(109), estimate.AnchorTick)
+
+	// 5 seconds after the start of tick 110 are 3.5 ticks of 2 seconds after tick 109
+	target, err := estimator.SuggestTargetTick(0.99)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(113), target)
+
+	// time spent since the update counts against the propagation time
+	now = now.Add(2 * time.Second)
+	target, err = estimator.SuggestTargetTick(0.99)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(114), target)
+
+	predicted, err := estimator.PredictTime(112)
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(24*time.Second), predicted)
+
+	_, err = estimator.SuggestTargetTick(1)
+	assert.Error(t, err)
+}
+
+func TestTickEstimator_IrregularTicks(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	// ticks alternate between 1 and 3 seconds
+	start := time.Date(2024, time.July, 3, 14, 30, 0, 0, time.UTC)
+	timestamp := start
+	for tick := uint32(100); tick < 111; tick++ {
+		setTickTimestamp(server, tick, timestamp)
+		timestamp = timestamp.Add(time.Duration(1+2*(tick%2)) * time.Second)
+	}
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 111, InitialTick: 100})
+
+	estimator := NewTickEstimator(client, TickEstimatorConfig{Window: 11, PropagationTime: 10 * time.Second})
+	// tick 110 lasted 1 second, tick 111 just started
+	now := timestamp
+	estimator.now = func() time.Time { return now }
+
+	estimate, err := estimator.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2*time.Second, estimate.TickDuration)
+	assert.InDelta(t, float64(time.Second), float64(estimate.Jitter), float64(100*time.Millisecond))
+
+	median, err := estimator.SuggestTargetTick(0.5)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(116), median)
+
+	safe, err := estimator.SuggestTargetTick(0.99)
+	require.NoError(t, err)
+	assert.Greater(t, safe, median)
+}
+
+func TestTickEstimator_StaleAnchor(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	start := time.Date(2024, time.July, 3, 14, 30, 0, 0, time.UTC)
+	for tick := uint32(100); tick < 110; tick++ {
+		setTickTimestamp(server, tick, start.Add(time.Duration(tick-100)*2*time.Second))
+	}
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 110, InitialTick: 100})
+
+	estimator := NewTickEstimator(client, TickEstimatorConfig{PropagationTime: 5 * time.Second})
+	// tick 110 started 4 seconds ago and is still running, tick 109 started 6 seconds ago
+	now := start.Add(24 * time.Second)
+	estimator.now = func() time.Time { return now }
+
+	estimate, err := estimator.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, uint32(109), estimate.AnchorTick)
+
+	// 11 seconds after the start of tick 109 are 5.5 ticks of 2 seconds, not 2.5 ticks after the current one
+	target, err := estimator.SuggestTargetTick(0.99)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(115), target)
+
+	// the target is always after the current tick, even when the clock is behind the anchor
+	now = start
+	target, err = estimator.SuggestTargetTick(0.99)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(111), target)
+}
+
+func TestTickEstimator_FallbackTickDuration(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 100, InitialTick: 100, TickDuration: 2500})
+
+	estimator := NewTickEstimator(client, TickEstimatorConfig{})
+
+	_, err := estimator.PredictTime(101)
+	assert.Error(t, err)
+
+	estimate, err := estimator.Update(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2500*time.Millisecond, estimate.TickDuration)
+
+	predicted, err := estimator.PredictTime(102)
+	require.NoError(t, err)
+	assert.Equal(t, estimate.ObservedAt.Add(5*time.Second), predicted)
+}
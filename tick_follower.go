@@ -0,0 +1,287 @@
+package qubic
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// TickFetcher is what the TickFollower needs from a node. *Client implements it and NewPoolTickFetcher adapts a *Pool.
+type TickFetcher interface {
+	GetTickInfo(ctx context.Context) (types.TickInfo, error)
+	GetTickData(ctx context.Context, tickNumber uint32) (types.TickData, error)
+	GetTickTransactions(ctx context.Context, tickNumber uint32) (types.Transactions, error)
+	GetQuorumVotes(ctx context.Context, tickNumber uint32) (types.QuorumVotes, error)
+	GetTxStatus(ctx context.Context, tick uint32) (types.TransactionStatus, error)
+}
+
+// FinalizedTick is everything the node knows about a completed tick, as reported by that node alone. With
+// NewPoolTickFetcher every tick is still fetched from a single node, consecutive ticks may come from different ones.
+type FinalizedTick struct {
+	Epoch uint16
+	Tick  uint32
+	// NewEpoch is set on the first tick emitted after the epoch or the initial tick of the node changed.
+	NewEpoch bool
+	// TickData is zero for an empty tick, see types.ErrEmptyTick.
+	TickData     types.TickData
+	Transactions types.Transactions
+	QuorumVotes  types.QuorumVotes
+	TxStatus     types.TransactionStatus
+}
+
+type TickFollowerConfig struct {
+	// StartTick is the first tick to emit. Zero starts at the initial tick of the current epoch. To resume, pass the
+	// tick after the last one processed.
+	StartTick uint32
+	// Concurrency is the number of ticks fetched at the same time, 1 when zero.
+	Concurrency int
+	// PollInterval is the time between two GetTickInfo calls once the follower caught up, and the time waited before
+	// retrying after an error. One second when zero.
+	PollInterval time.Duration
+}
+
+// TickFollower polls the node tick info and emits every completed tick, in order and exactly once.
+type TickFollower struct {
+	fetcher TickFetcher
+	config  TickFollowerConfig
+}
+
+func NewTickFollower(fetcher TickFetcher, config TickFollowerConfig) *TickFollower {
+	if config.Concurrency <= 0 {
+		config.Concurrency = 1
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = time.Second
+	}
+
+	return &TickFollower{fetcher: fetcher, config: config}
+}
+
+// Run emits the finalized ticks on out until ctx is done and returns the context error. A tick is considered final
+// once the node reports a later current tick, quorum is not checked: emitted ticks are only as trustworthy as the node
+// they come from. Pass FinalizedTick.QuorumVotes to CheckQuorum to verify them. When the epoch or the initial tick
+// changes the follower jumps to the new initial tick, ticks of the previous epoch that were not emitted yet are skipped
+// since nodes no longer serve them.
+func (tf *TickFollower) Run(ctx context.Context, out chan<- FinalizedTick) error {
+	next := tf.config.StartTick
+	newEpoch := false
+	var epoch uint16
+	var initialTick uint32
+	started := false
+
+	for {
+		tickInfo, err := tf.fetcher.GetTickInfo(ctx)
+		if err != nil {
+			if err := tf.wait(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		switch {
+		case !started:
+			started = true
+			epoch, initialTick = tickInfo.Epoch, tickInfo.InitialTick
+			if next < initialTick {
+				// resuming from a previous epoch
+				newEpoch = next != 0
+				next = initialTick
+			}
+		case tickInfo.Epoch > epoch || (tickInfo.Epoch == epoch && tickInfo.InitialTick > initialTick):
+			epoch, initialTick = tickInfo.Epoch, tickInfo.InitialTick
+			newEpoch = true
+			next = initialTick
+		case tickInfo.Epoch < epoch || tickInfo.InitialTick < initialTick:
+			// a node behind the others, e.g. in a pool, not worth following
+			if err := tf.wait(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if next >= tickInfo.Tick {
+			if err := tf.wait(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+
+		last := tickInfo.Tick - 1
+		if last-next >= uint32(tf.config.Concurrency) {
+			last = next + uint32(tf.config.Concurrency) - 1
+		}
+
+		ticks, err := tf.fetchTicks(ctx, next, last)
+		for _, tick := range ticks {
+			tick.Epoch = epoch
+			tick.NewEpoch = newEpoch
+			newEpoch = false
+
+			select {
+			case out <- tick:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			next = tick.Tick + 1
+		}
+
+		if err != nil {
+			if err := tf.wait(ctx); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// fetchTicks fetches the ticks from first to last concurrently. It returns the ticks fetched before the first failing
+// one, so the caller can emit them and retry from there.
+func (tf *TickFollower) fetchTicks(ctx context.Context, first, last uint32) ([]FinalizedTick, error) {
+	count := int(last-first) + 1
+	ticks := make([]FinalizedTick, count)
+	errs := make([]error, count)
+
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ticks[i], errs[i] = tf.fetchTick(ctx, first+uint32(i))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return ticks[:i], errors.Wrapf(err, "fetching tick %d", first+uint32(i))
+		}
+	}
+
+	return ticks, nil
+}
+
+// singleNodeTickFetcher is implemented by fetchers spreading their calls over several nodes, to fetch all of one tick
+// from the same node.
+type singleNodeTickFetcher interface {
+	fetchTick(ctx context.Context, tick uint32) (FinalizedTick, error)
+}
+
+func (tf *TickFollower) fetchTick(ctx context.Context, tick uint32) (FinalizedTick, error) {
+	if fetcher, ok := tf.fetcher.(singleNodeTickFetcher); ok {
+		return fetcher.fetchTick(ctx, tick)
+	}
+
+	return fetchTick(ctx, tf.fetcher, tick)
+}
+
+func fetchTick(ctx context.Context, fetcher TickFetcher, tick uint32) (FinalizedTick, error) {
+	// empty ticks are emitted too, with zero tick data
+	tickData, err := fetcher.GetTickData(ctx, tick)
+	if err != nil && !errors.Is(err, types.ErrEmptyTick) {
+		return FinalizedTick{}, errors.Wrap(err, "getting tick data")
+	}
+
+	txs, err := fetcher.GetTickTransactions(ctx, tick)
+	if err != nil {
+		return FinalizedTick{}, errors.Wrap(err, "getting tick transactions")
+	}
+
+	votes, err := fetcher.GetQuorumVotes(ctx, tick)
+	if err != nil {
+		return FinalizedTick{}, errors.Wrap(err, "getting quorum votes")
+	}
+
+	txStatus, err := fetcher.GetTxStatus(ctx, tick)
+	if err != nil {
+		return FinalizedTick{}, errors.Wrap(err, "getting tx status")
+	}
+
+	return FinalizedTick{
+		Tick:         tick,
+		TickData:     tickData,
+		Transactions: txs,
+		QuorumVotes:  votes,
+		TxStatus:     txStatus,
+	}, nil
+}
+
+func (tf *TickFollower) wait(ctx context.Context) error {
+	timer := time.NewTimer(tf.config.PollInterval)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// poolTickFetcher runs every call with Pool.DoIdempotent, so failed calls are retried. The TickFollower fetches each
+// tick with a single client, so that the tick data, transactions and votes of a tick come from the same node.
+type poolTickFetcher struct {
+	pool *Pool
+}
+
+func NewPoolTickFetcher(pool *Pool) TickFetcher {
+	return &poolTickFetcher{pool: pool}
+}
+
+func (pf *poolTickFetcher) fetchTick(ctx context.Context, tick uint32) (finalized FinalizedTick, err error) {
+	err = pf.pool.DoIdempotent(ctx, func(client *Client) error {
+		finalized, err = fetchTick(ctx, client, tick)
+		return err
+	})
+	return finalized, err
+}
+
+func (pf *poolTickFetcher) GetTickInfo(ctx context.Context) (tickInfo types.TickInfo, err error) {
+	err = pf.pool.DoIdempotent(ctx, func(client *Client) error {
+		tickInfo, err = client.GetTickInfo(ctx)
+		return err
+	})
+	return tickInfo, err
+}
+
+func (pf *poolTickFetcher) GetTickData(ctx context.Context, tickNumber uint32) (tickData types.TickData, err error) {
+	var emptyErr error
+	err = pf.pool.DoIdempotent(ctx, func(client *Client) error {
+		tickData, err = client.GetTickData(ctx, tickNumber)
+		// an empty tick is an answer, the client is fine and another node would not know better
+		if errors.Is(err, types.ErrEmptyTick) {
+			emptyErr = err
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		return tickData, err
+	}
+	return tickData, emptyErr
+}
+
+func (pf *poolTickFetcher) GetTickTransactions(ctx context.Context, tickNumber uint32) (txs types.Transactions, err error) {
+	err = pf.pool.DoIdempotent(ctx, func(client *Client) error {
+		txs, err = client.GetTickTransactions(ctx, tickNumber)
+		return err
+	})
+	return txs, err
+}
+
+func (pf *poolTickFetcher) GetQuorumVotes(ctx context.Context, tickNumber uint32) (votes types.QuorumVotes, err error) {
+	err = pf.pool.DoIdempotent(ctx, func(client *Client) error {
+		votes, err = client.GetQuorumVotes(ctx, tickNumber)
+		return err
+	})
+	return votes, err
+}
+
+func (pf *poolTickFetcher) GetTxStatus(ctx context.Context, tick uint32) (txStatus types.TransactionStatus, err error) {
+	err = pf.pool.DoIdempotent(ctx, func(client *Client) error {
+		txStatus, err = client.GetTxStatus(ctx, tick)
+		return err
+	})
+	return txStatus, err
+}
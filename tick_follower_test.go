@@ -0,0 +1,134 @@
+package qubic
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setTestTick(t *testing.T, server *qubictest.Server, epoch uint16, tick uint32) {
+	tx := types.Transaction{Tick: tick, Amount: int64(tick), Input: []byte{}, Signature: [64]byte{1}}
+	digest, err := tx.Digest()
+	require.NoError(t, err)
+
+	server.SetTickData(types.TickData{Epoch: epoch, Tick: tick, TransactionDigests: [types.NumberOfTransactionsPerTick]types.Digest{digest}})
+	server.SetTickTransactions(tick, types.Transactions{tx})
+	server.SetQuorumVotes(tick, types.QuorumVotes{{Epoch: epoch, Tick: tick}})
+	server.SetTxStatus(types.TransactionStatus{Tick: tick, TxCount: 1, TransactionDigests: []types.Digest{digest}})
+}
+
+func receiveTick(t *testing.T, ticks <-chan FinalizedTick) FinalizedTick {
+	select {
+	case tick := <-ticks:
+		return tick
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "no tick received")
+	}
+
+	return FinalizedTick{}
+}
+
+func TestTickFollower_Run(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	for tick := uint32(100); tick < 110; tick++ {
+		setTestTick(t, server, 150, tick)
+	}
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 106, InitialTick: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	follower := NewTickFollower(client, TickFollowerConfig{Concurrency: 4, PollInterval: 10 * time.Millisecond})
+	ticks := make(chan FinalizedTick)
+	done := make(chan error)
+	go func() { done <- follower.Run(ctx, ticks) }()
+
+	for tick := uint32(100); tick < 106; tick++ {
+		got := receiveTick(t, ticks)
+		assert.Equal(t, tick, got.Tick)
+		assert.Equal(t, uint16(150), got.Epoch)
+		assert.False(t, got.NewEpoch)
+		assert.Equal(t, tick, got.TickData.Tick)
+		require.Len(t, got.Transactions, 1)
+		assert.Equal(t, int64(tick), got.Transactions[0].Amount)
+		assert.Len(t, got.QuorumVotes, 1)
+		assert.Equal(t, uint32(1), got.TxStatus.TxCount)
+	}
+
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 108, InitialTick: 100})
+	assert.Equal(t, uint32(106), receiveTick(t, ticks).Tick)
+	assert.Equal(t, uint32(107), receiveTick(t, ticks).Tick)
+
+	setTestTick(t, server, 151, 200)
+	server.SetTickInfo(types.TickInfo{Epoch: 151, Tick: 201, InitialTick: 200})
+	got := receiveTick(t, ticks)
+	assert.Equal(t, uint32(200), got.Tick)
+	assert.Equal(t, uint16(151), got.Epoch)
+	assert.True(t, got.NewEpoch)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestTickFollower_Resume(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+
+	for tick := uint32(100); tick < 110; tick++ {
+		setTestTick(t, server, 150, tick)
+	}
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 110, InitialTick: 100})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ticks := make(chan FinalizedTick)
+	go NewTickFollower(client, TickFollowerConfig{StartTick: 107}).Run(ctx, ticks)
+
+	for tick := uint32(107); tick < 110; tick++ {
+		assert.Equal(t, tick, receiveTick(t, ticks).Tick)
+	}
+}
+
+func TestTickFollower_PoolFetchesTickFromOneNode(t *testing.T) {
+	var nodes []*qubictest.Server
+	for index := uint16(1); index <= 2; index++ {
+		node := newTestNode(t, 110)
+		node.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 110, InitialTick: 100})
+		for tick := uint32(100); tick < 110; tick++ {
+			node.SetTickData(types.TickData{ComputorIndex: index, Epoch: 150, Tick: tick})
+			node.SetQuorumVotes(tick, types.QuorumVotes{{ComputorIndex: index, Epoch: 150, Tick: tick}})
+		}
+		nodes = append(nodes, node)
+	}
+	fetcher := newTestNodeFetcher(t, 110, nodes...)
+
+	p, err := NewPoolConnection(PoolConfig{
+		MaxCap:             5,
+		MaxIdle:            5,
+		IdleTimeout:        time.Minute,
+		NodeFetcherUrl:     fetcher.URL,
+		NodeFetcherTimeout: 5 * time.Second,
+	})
+	require.NoError(t, err)
+	defer p.Release()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	follower := NewTickFollower(NewPoolTickFetcher(p), TickFollowerConfig{Concurrency: 4, PollInterval: 10 * time.Millisecond})
+	ticks := make(chan FinalizedTick)
+	go follower.Run(ctx, ticks)
+
+	for tick := uint32(100); tick < 110; tick++ {
+		got := receiveTick(t, ticks)
+		assert.Equal(t, tick, got.Tick)
+		require.Len(t, got.QuorumVotes, 1)
+		assert.Equal(t, got.TickData.ComputorIndex, got.QuorumVotes[0].ComputorIndex)
+	}
+}
@@ -0,0 +1,234 @@
+package qubic
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/qubic/go-node-connector/types"
+)
+
+// txTrackingPollInterval is the time between two tick info polls while waiting for the tick of a transaction
+var txTrackingPollInterval = time.Second
+
+// TxInclusion is the outcome of a transaction once its tick is over.
+type TxInclusion int
+
+const (
+	// TxNotIncluded means the transaction is not part of its tick, it has to be sent again for a later tick.
+	TxNotIncluded TxInclusion = iota
+	// TxIncluded means the transaction is part of its tick but no money moved, e.g. the source balance was too low.
+	TxIncluded
+	// TxMoneyFlew means the transaction is part of its tick and its amount was transferred.
+	TxMoneyFlew
+)
+
+func (i TxInclusion) String() string {
+	switch i {
+	case TxNotIncluded:
+		return "not included"
+	case TxIncluded:
+		return "included"
+	case TxMoneyFlew:
+		return "money flew"
+	}
+
+	return "unknown"
+}
+
+type TxReceipt struct {
+	TxID      string
+	Digest    types.Digest
+	Tick      uint32
+	Inclusion TxInclusion
+	// Epoch is the epoch of the node when the inclusion was checked.
+	Epoch uint16
+}
+
+// SubmitAndTrack broadcasts a signed transaction, waits until the node is past the transaction tick and reports
+// whether the transaction made it into the tick. A transaction whose tick was skipped because the epoch changed or
+// the network restarted at a later tick is reported as not included.
+func (qc *Client) SubmitAndTrack(ctx context.Context, tx types.Transaction) (*TxReceipt, error) {
+	if tx.Signature == [64]byte{} {
+		return nil, types.ErrTxNotSigned
+	}
+
+	digest, err := tx.Digest()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting tx digest")
+	}
+
+	id, err := tx.ID()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting tx id")
+	}
+
+	rawTx, err := tx.MarshallBinary()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling tx")
+	}
+
+	err = qc.SendRawTransaction(ctx, rawTx)
+	if err != nil {
+		return nil, errors.Wrap(err, "sending tx")
+	}
+
+	receipt := TxReceipt{TxID: id, Digest: digest, Tick: tx.Tick}
+
+	tickInfo, err := qc.waitForTickAfter(ctx, tx.Tick)
+	if err != nil {
+		return nil, errors.Wrapf(err, "waiting for tick %d", tx.Tick)
+	}
+	receipt.Epoch = tickInfo.Epoch
+
+	if tickInfo.InitialTick > tx.Tick {
+		return &receipt, nil
+	}
+
+	receipt.Inclusion, err = qc.getTxInclusion(ctx, tx.Tick, digest)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting tx inclusion")
+	}
+
+	return &receipt, nil
+}
+
+// waitForTickAfter polls the tick info until the node is past the given tick.
+func (qc *Client) waitForTickAfter(ctx context.Context, tick uint32) (types.TickInfo, error) {
+	ticker := time.NewTicker(txTrackingPollInterval)
+	defer ticker.Stop()
+
+	for {
+		tickInfo, err := qc.GetTickInfo(ctx)
+		if err != nil {
+			return types.TickInfo{}, errors.Wrap(err, "getting tick info")
+		}
+
+		if tickInfo.Tick > tick || tickInfo.InitialTick > tick {
+			return tickInfo, nil
+		}
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return types.TickInfo{}, ctx.Err()
+		}
+	}
+}
+
+func (qc *Client) getTxInclusion(ctx context.Context, tick uint32, digest types.Digest) (TxInclusion, error) {
+	tickData, err := qc.GetTickData(ctx, tick)
+	if errors.Is(err, types.ErrEmptyTick) {
+		return TxNotIncluded, nil
+	}
+	if err != nil {
+		return TxNotIncluded, errors.Wrap(err, "getting tick data")
+	}
+
+	if !containsDigest(tickData.TransactionDigests[:], digest) {
+		return TxNotIncluded, nil
+	}
+
+	return qc.getMoneyFlew(ctx, tick, digest)
+}
+
+// getMoneyFlew tells TxMoneyFlew and TxIncluded apart for a transaction known to be part of its tick.
+func (qc *Client) getMoneyFlew(ctx context.Context, tick uint32, digest types.Digest) (TxInclusion, error) {
+	txStatus, err := qc.GetTxStatus(ctx, tick)
+	if err != nil {
+		return TxNotIncluded, errors.Wrap(err, "getting tx status")
+	}
+
+	// the money flew bitmap is indexed by the position of the digest in the tx status, not in the tick data
+	for i, statusDigest := range txStatus.TransactionDigests {
+		if statusDigest != digest {
+			continue
+		}
+
+		if i < len(txStatus.MoneyFlew)*8 && txStatus.MoneyFlew[i/8]&(1<<(i%8)) != 0 {
+			return TxMoneyFlew, nil
+		}
+		break
+	}
+
+	return TxIncluded, nil
+}
+
+func containsDigest(digests []types.Digest, digest types.Digest) bool {
+	for _, d := range digests {
+		if d == digest {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetTransactionByID returns the transaction with the given id from its tick and whether its amount was transferred.
+// Only the slot of the transaction is requested from the node instead of every transaction of the tick.
+func (qc *Client) GetTransactionByID(ctx context.Context, txID string, tick uint32) (types.Transaction, TxInclusion, error) {
+	digest, err := types.ParseDigest(txID)
+	if err != nil {
+		return types.Transaction{}, TxNotIncluded, errors.Wrap(err, "parsing tx id")
+	}
+	// empty slots of the tick data hold the zero digest
+	if digest.IsZero() {
+		return types.Transaction{}, TxNotIncluded, errors.Wrapf(ErrTxNotFound, "tx %s has a zero digest", txID)
+	}
+
+	tickData, err := qc.GetTickData(ctx, tick)
+	if errors.Is(err, types.ErrEmptyTick) {
+		return types.Transaction{}, TxNotIncluded, errors.Wrapf(ErrTxNotFound, "tick %d is empty", tick)
+	}
+	if err != nil {
+		return types.Transaction{}, TxNotIncluded, errors.Wrap(err, "getting tick data")
+	}
+
+	slot := -1
+	for i, d := range tickData.TransactionDigests {
+		if d == digest {
+			slot = i
+			break
+		}
+	}
+	if slot == -1 {
+		return types.Transaction{}, TxNotIncluded, errors.Wrapf(ErrTxNotFound, "tx %s in tick %d", txID, tick)
+	}
+
+	request := struct {
+		Tick             uint32
+		TransactionFlags [types.NumberOfTransactionsPerTick / 8]uint8
+	}{Tick: tick}
+
+	// a set flag skips the transaction, only the slot of the transaction is left clear
+	for i := range request.TransactionFlags {
+		request.TransactionFlags[i] = 0xff
+	}
+	request.TransactionFlags[slot/8] &^= 1 << (slot % 8)
+
+	var result types.Transactions
+	err = qc.sendRequest(ctx, types.TickTransactionsRequest, request, &result)
+	if err != nil {
+		return types.Transaction{}, TxNotIncluded, errors.Wrap(err, "sending transaction req")
+	}
+
+	for _, tx := range result {
+		txDigest, err := tx.Digest()
+		if err != nil {
+			return types.Transaction{}, TxNotIncluded, errors.Wrap(err, "getting tx digest")
+		}
+
+		if txDigest != digest {
+			return types.Transaction{}, TxNotIncluded, errors.Errorf("node returned tx %s instead of %s", txDigest, txID)
+		}
+
+		inclusion, err := qc.getMoneyFlew(ctx, tick, digest)
+		if err != nil {
+			return types.Transaction{}, TxNotIncluded, errors.Wrap(err, "getting tx inclusion")
+		}
+
+		return tx, inclusion, nil
+	}
+
+	return types.Transaction{}, TxNotIncluded, errors.Wrapf(ErrTxNotFound, "tx %s not returned by node for tick %d", txID, tick)
+}
@@ -0,0 +1,180 @@
+package qubic
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/qubic/go-node-connector/qubictest"
+	"github.com/qubic/go-node-connector/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedTestTx(t *testing.T, tick uint32) types.Transaction {
+	seed := strings.Repeat("a", 55)
+	wallet, err := types.NewWallet(seed)
+	require.NoError(t, err)
+
+	tx := types.Transaction{SourcePublicKey: wallet.PubKey, DestinationPublicKey: [32]byte{1}, Amount: 10, Tick: tick, Input: []byte{}}
+	require.NoError(t, tx.Sign(seed))
+
+	return tx
+}
+
+// submitAndTrack runs SubmitAndTrack in the background and returns once the node received the transaction.
+func submitAndTrack(t *testing.T, server *qubictest.Server, client *Client, tx types.Transaction) <-chan *TxReceipt {
+	receipts := make(chan *TxReceipt, 1)
+	go func() {
+		receipt, err := client.SubmitAndTrack(context.Background(), tx)
+		assert.NoError(t, err)
+		receipts <- receipt
+	}()
+
+	require.Eventually(t, func() bool {
+		return len(server.BroadcastTransactions()) == 1
+	}, 2*time.Second, time.Millisecond)
+	assert.Equal(t, tx, server.BroadcastTransactions()[0])
+
+	return receipts
+}
+
+func receiveReceipt(t *testing.T, receipts <-chan *TxReceipt) *TxReceipt {
+	select {
+	case receipt := <-receipts:
+		require.NotNil(t, receipt)
+		return receipt
+	case <-time.After(2 * time.Second):
+		require.FailNow(t, "no receipt")
+	}
+
+	return nil
+}
+
+func TestClient_SubmitAndTrack(t *testing.T) {
+	txTrackingPollInterval = 5 * time.Millisecond
+	defer func() { txTrackingPollInterval = time.Second }()
+
+	tx := newSignedTestTx(t, 105)
+	digest, err := tx.Digest()
+	require.NoError(t, err)
+	other := types.Digest{9}
+
+	testCases := []struct {
+		name              string
+		tickInfo          types.TickInfo
+		tickDigests       []types.Digest
+		statusDigests     []types.Digest
+		moneyFlew         byte
+		expectedInclusion TxInclusion
+	}{
+		{
+			name:              "money flew",
+			tickInfo:          types.TickInfo{Epoch: 150, Tick: 106, InitialTick: 100},
+			tickDigests:       []types.Digest{other, digest},
+			statusDigests:     []types.Digest{digest},
+			moneyFlew:         0b1,
+			expectedInclusion: TxMoneyFlew,
+		},
+		{
+			name:              "included without money flow",
+			tickInfo:          types.TickInfo{Epoch: 150, Tick: 106, InitialTick: 100},
+			tickDigests:       []types.Digest{other, digest},
+			statusDigests:     []types.Digest{other, digest},
+			moneyFlew:         0b1,
+			expectedInclusion: TxIncluded,
+		},
+		{
+			name:              "not in tick",
+			tickInfo:          types.TickInfo{Epoch: 150, Tick: 106, InitialTick: 100},
+			tickDigests:       []types.Digest{other},
+			expectedInclusion: TxNotIncluded,
+		},
+		{
+			name:              "tick skipped by new epoch",
+			tickInfo:          types.TickInfo{Epoch: 151, Tick: 200, InitialTick: 200},
+			expectedInclusion: TxNotIncluded,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, client := newFakeNodeClient(t)
+			server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 100, InitialTick: 100})
+
+			receipts := submitAndTrack(t, server, client, tx)
+
+			tickData := types.TickData{Epoch: 150, Tick: 105}
+			copy(tickData.TransactionDigests[:], tc.tickDigests)
+			server.SetTickData(tickData)
+			server.SetTxStatus(types.TransactionStatus{Tick: 105, MoneyFlew: [128]byte{tc.moneyFlew}, TransactionDigests: tc.statusDigests})
+			server.SetTickInfo(tc.tickInfo)
+
+			receipt := receiveReceipt(t, receipts)
+			assert.Equal(t, tc.expectedInclusion, receipt.Inclusion)
+			assert.Equal(t, digest, receipt.Digest)
+			assert.Equal(t, uint32(105), receipt.Tick)
+			assert.Equal(t, tc.tickInfo.Epoch, receipt.Epoch)
+		})
+	}
+}
+
+func TestClient_SubmitAndTrackUnsigned(t *testing.T) {
+	_, client := newFakeNodeClient(t)
+
+	_, err := client.SubmitAndTrack(context.Background(), types.Transaction{Tick: 105})
+	assert.Error(t, err)
+}
+
+func TestClient_GetTransactionByID(t *testing.T) {
+	server, client := newFakeNodeClient(t)
+	server.SetTickInfo(types.TickInfo{Epoch: 150, Tick: 106, InitialTick: 100})
+
+	txs := types.Transactions{newSignedTestTx(t, 105), newSignedTestTx(t, 105), newSignedTestTx(t, 105)}
+	txs[1].Amount = 20
+	require.NoError(t, txs[1].Sign(strings.Repeat("a", 55)))
+	txs[2].Amount = 30
+	require.NoError(t, txs[2].Sign(strings.Repeat("a", 55)))
+
+	tickData := types.TickData{Epoch: 150, Tick: 105}
+	for i, tx := range txs {
+		digest, err := tx.Digest()
+		require.NoError(t, err)
+		tickData.TransactionDigests[i] = digest
+	}
+	server.SetTickData(tickData)
+	server.SetTickTransactions(105, txs)
+	server.SetTxStatus(types.TransactionStatus{Tick: 105, MoneyFlew: [128]byte{0b10}, TransactionDigests: tickData.TransactionDigests[:3]})
+
+	// the server skips the flagged slots, getting the other transactions of the tick would fail the digest check
+	id, err := txs[1].ID()
+	require.NoError(t, err)
+	tx, inclusion, err := client.GetTransactionByID(context.Background(), id, 105)
+	require.NoError(t, err)
+	assert.Equal(t, txs[1], tx)
+	assert.Equal(t, TxMoneyFlew, inclusion)
+
+	id, err = txs[2].ID()
+	require.NoError(t, err)
+	tx, inclusion, err = client.GetTransactionByID(context.Background(), id, 105)
+	require.NoError(t, err)
+	assert.Equal(t, txs[2], tx)
+	assert.Equal(t, TxIncluded, inclusion)
+
+	other := newSignedTestTx(t, 104)
+	id, err = other.ID()
+	require.NoError(t, err)
+	_, _, err = client.GetTransactionByID(context.Background(), id, 105)
+	assert.ErrorIs(t, err, ErrTxNotFound)
+
+	_, _, err = client.GetTransactionByID(context.Background(), types.Digest{}.String(), 105)
+	assert.ErrorIs(t, err, ErrTxNotFound)
+
+	// the node returns another transaction than the one in the tick data
+	server.SetTickTransactions(105, types.Transactions{txs[0], txs[2], txs[1]})
+	id, err = txs[1].ID()
+	require.NoError(t, err)
+	_, _, err = client.GetTransactionByID(context.Background(), id, 105)
+	assert.Error(t, err)
+}
@@ -36,7 +36,7 @@ func (ai *AddressInfo) UnmarshallFromReader(r io.Reader) error {
 	}
 
 	if header.Type != BalanceTypeResponse {
-		return errors.Errorf("Invalid header type, expected %d, found %d", BalanceTypeResponse, header.Type)
+		return ErrUnexpectedPacket{Expected: BalanceTypeResponse, Got: header.Type}
 	}
 
 	err = binary.Read(r, binary.LittleEndian, ai)
@@ -46,3 +46,44 @@ func (ai *AddressInfo) UnmarshallFromReader(r io.Reader) error {
 
 	return nil
 }
+
+// GetSpectrumDigest rebuilds the spectrum digest from the entity, its spectrum index and the merkle siblings.
+func (ai *AddressInfo) GetSpectrumDigest() ([32]byte, error) {
+	if ai.SpectrumIndex < 0 {
+		return [32]byte{}, errors.Errorf("entity is not in the spectrum, index %d", ai.SpectrumIndex)
+	}
+
+	leaf, err := getLeafDigest(ai.AddressData)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "getting entity digest")
+	}
+
+	root, err := getMerkleRoot(leaf, uint32(ai.SpectrumIndex), ai.Siblings[:])
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "getting spectrum merkle root")
+	}
+
+	return root, nil
+}
+
+// VerifyAgainst checks that the entity is proven by the given spectrum digest, such as
+// QuorumTickVote.PreviousSpectrumDigest.
+func (ai *AddressInfo) VerifyAgainst(spectrumDigest [32]byte) error {
+	root, err := ai.GetSpectrumDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting spectrum digest")
+	}
+
+	return verifyRoot(root, spectrumDigest)
+}
+
+// VerifyAgainstSalted checks that the entity is proven by a salted spectrum digest, such as
+// QuorumTickVote.SaltedSpectrumDigest, signed by the computor with the given public key.
+func (ai *AddressInfo) VerifyAgainstSalted(saltedSpectrumDigest [32]byte, computorPubKey [32]byte) error {
+	root, err := ai.GetSpectrumDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting spectrum digest")
+	}
+
+	return verifySaltedRoot(root, saltedSpectrumDigest, computorPubKey)
+}
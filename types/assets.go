@@ -35,6 +35,27 @@ func (ai *AssetInfo) UnmarshallBinary(r io.Reader) error {
 	return nil
 }
 
+// UniverseRecord is an entry of the universe: an issuance, ownership or possession record.
+type UniverseRecord interface {
+	// GetLeafDigest returns the K12 digest of the record as stored in the universe.
+	GetLeafDigest() ([32]byte, error)
+}
+
+// GetUniverseDigest rebuilds the universe digest from the digest of an asset record and the merkle siblings.
+func (ai *AssetInfo) GetUniverseDigest(record UniverseRecord) ([32]byte, error) {
+	leaf, err := record.GetLeafDigest()
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "getting asset record digest")
+	}
+
+	root, err := getMerkleRoot(leaf, ai.UniverseIndex, ai.Siblings[:])
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "getting universe merkle root")
+	}
+
+	return root, nil
+}
+
 /* Issued asset */
 
 type IssuedAssetData struct {
@@ -52,6 +73,33 @@ type IssuedAsset struct {
 
 type IssuedAssets []IssuedAsset
 
+// GetUniverseDigest rebuilds the universe digest proving the issuance record.
+func (a *IssuedAsset) GetUniverseDigest() ([32]byte, error) {
+	return a.Info.GetUniverseDigest(&a.Data)
+}
+
+// VerifyAgainst checks that the asset is proven by the given universe digest, such as
+// QuorumTickVote.PreviousUniverseDigest.
+func (a *IssuedAsset) VerifyAgainst(universeDigest [32]byte) error {
+	root, err := a.GetUniverseDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting universe digest")
+	}
+
+	return verifyRoot(root, universeDigest)
+}
+
+// VerifyAgainstSalted checks that the asset is proven by a salted universe digest, such as
+// QuorumTickVote.SaltedUniverseDigest, signed by the computor with the given public key.
+func (a *IssuedAsset) VerifyAgainstSalted(saltedUniverseDigest [32]byte, computorPubKey [32]byte) error {
+	root, err := a.GetUniverseDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting universe digest")
+	}
+
+	return verifySaltedRoot(root, saltedUniverseDigest, computorPubKey)
+}
+
 func (ia *IssuedAssets) UnmarshallFromReader(r io.Reader) error {
 	for {
 		var header RequestResponseHeader
@@ -65,7 +113,11 @@ func (ia *IssuedAssets) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != IssuedAssetsResponse {
-			return errors.Errorf("Invalid header type, expected %d, found %d", IssuedAssetsResponse, header.Type)
+			return ErrUnexpectedPacket{Expected: IssuedAssetsResponse, Got: header.Type}
+		}
+
+		if len(*ia) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
 		}
 
 		var issuedAssetData IssuedAssetData
@@ -91,6 +143,10 @@ func (ia *IssuedAssets) UnmarshallFromReader(r io.Reader) error {
 	return nil
 }
 
+func (ad *IssuedAssetData) GetLeafDigest() ([32]byte, error) {
+	return getLeafDigest(ad)
+}
+
 func (ad *IssuedAssetData) UnmarshallBinary(r io.Reader) error {
 
 	err := binary.Read(r, binary.LittleEndian, &ad.PublicKey)
@@ -139,6 +195,46 @@ type PossessedAsset struct {
 
 type PossessedAssets []PossessedAsset
 
+// GetUniverseDigest rebuilds the universe digest proving the possession record.
+func (a *PossessedAsset) GetUniverseDigest() ([32]byte, error) {
+	record := a.Data.record()
+	return a.Info.GetUniverseDigest(&record)
+}
+
+// VerifyAgainst checks that the asset is proven by the given universe digest, such as
+// QuorumTickVote.PreviousUniverseDigest.
+func (a *PossessedAsset) VerifyAgainst(universeDigest [32]byte) error {
+	root, err := a.GetUniverseDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting universe digest")
+	}
+
+	return verifyRoot(root, universeDigest)
+}
+
+// VerifyAgainstSalted checks that the asset is proven by a salted universe digest, such as
+// QuorumTickVote.SaltedUniverseDigest, signed by the computor with the given public key.
+func (a *PossessedAsset) VerifyAgainstSalted(saltedUniverseDigest [32]byte, computorPubKey [32]byte) error {
+	root, err := a.GetUniverseDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting universe digest")
+	}
+
+	return verifySaltedRoot(root, saltedUniverseDigest, computorPubKey)
+}
+
+// record returns the 48 byte possession record stored in the universe, without the owned asset appended by the node.
+func (ad *PossessedAssetData) record() AssetPossessionData {
+	return AssetPossessionData{
+		PublicKey:             ad.PublicKey,
+		Type:                  ad.Type,
+		Padding:               ad.Padding,
+		ManagingContractIndex: ad.ManagingContractIndex,
+		OwnershipIndex:        ad.IssuanceIndex, // possession records keep the ownership index in this slot
+		NumberOfUnits:         ad.NumberOfUnits,
+	}
+}
+
 func (pa *PossessedAssets) UnmarshallFromReader(r io.Reader) error {
 	for {
 		var header RequestResponseHeader
@@ -152,7 +248,11 @@ func (pa *PossessedAssets) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != PossessedAssetsResponse {
-			return errors.Errorf("Invalid header type, expected %d, found %d", PossessedAssetsResponse, header.Type)
+			return ErrUnexpectedPacket{Expected: PossessedAssetsResponse, Got: header.Type}
+		}
+
+		if len(*pa) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
 		}
 
 		var possessedAssetData PossessedAssetData
@@ -237,6 +337,46 @@ type OwnedAsset struct {
 
 type OwnedAssets []OwnedAsset
 
+// GetUniverseDigest rebuilds the universe digest proving the ownership record.
+func (a *OwnedAsset) GetUniverseDigest() ([32]byte, error) {
+	record := a.Data.record()
+	return a.Info.GetUniverseDigest(&record)
+}
+
+// VerifyAgainst checks that the asset is proven by the given universe digest, such as
+// QuorumTickVote.PreviousUniverseDigest.
+func (a *OwnedAsset) VerifyAgainst(universeDigest [32]byte) error {
+	root, err := a.GetUniverseDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting universe digest")
+	}
+
+	return verifyRoot(root, universeDigest)
+}
+
+// VerifyAgainstSalted checks that the asset is proven by a salted universe digest, such as
+// QuorumTickVote.SaltedUniverseDigest, signed by the computor with the given public key.
+func (a *OwnedAsset) VerifyAgainstSalted(saltedUniverseDigest [32]byte, computorPubKey [32]byte) error {
+	root, err := a.GetUniverseDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting universe digest")
+	}
+
+	return verifySaltedRoot(root, saltedUniverseDigest, computorPubKey)
+}
+
+// record returns the 48 byte ownership record stored in the universe, without the issued asset appended by the node.
+func (ad *OwnedAssetData) record() AssetOwnershipData {
+	return AssetOwnershipData{
+		PublicKey:             ad.PublicKey,
+		Type:                  ad.Type,
+		Padding:               ad.Padding,
+		ManagingContractIndex: ad.ManagingContractIndex,
+		IssuanceIndex:         ad.IssuanceIndex,
+		NumberOfUnits:         ad.NumberOfUnits,
+	}
+}
+
 func (oa *OwnedAssets) UnmarshallFromReader(r io.Reader) error {
 	for {
 		var header RequestResponseHeader
@@ -250,7 +390,11 @@ func (oa *OwnedAssets) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != OwnedAssetsResponse {
-			return errors.Errorf("Invalid header type, expected %d, found %d", OwnedAssetsResponse, header.Type)
+			return ErrUnexpectedPacket{Expected: OwnedAssetsResponse, Got: header.Type}
+		}
+
+		if len(*oa) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
 		}
 
 		var ownedAssetData OwnedAssetData
@@ -347,7 +491,11 @@ func (ia *AssetIssuances) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != RespondAssets {
-			return errors.Errorf("Invalid header type, expected %d, found %d", RespondAssets, header.Type)
+			return ErrUnexpectedPacket{Expected: RespondAssets, Got: header.Type}
+		}
+
+		if len(*ia) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
 		}
 
 		var issuedAssetData AssetIssuanceData
@@ -380,6 +528,10 @@ func (ia *AssetIssuances) UnmarshallFromReader(r io.Reader) error {
 	return nil
 }
 
+func (ad *AssetIssuanceData) GetLeafDigest() ([32]byte, error) {
+	return getLeafDigest(ad)
+}
+
 func (ad *AssetIssuanceData) UnmarshallBinary(r io.Reader) error {
 
 	err := binary.Read(r, binary.LittleEndian, &ad.PublicKey)
@@ -441,7 +593,11 @@ func (oa *AssetOwnerships) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != RespondAssets {
-			return errors.Errorf("Invalid header type, expected %d, found %d", RespondAssets, header.Type)
+			return ErrUnexpectedPacket{Expected: RespondAssets, Got: header.Type}
+		}
+
+		if len(*oa) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
 		}
 
 		var assetOwnershipData AssetOwnershipData
@@ -474,6 +630,10 @@ func (oa *AssetOwnerships) UnmarshallFromReader(r io.Reader) error {
 	return nil
 }
 
+func (ad *AssetOwnershipData) GetLeafDigest() ([32]byte, error) {
+	return getLeafDigest(ad)
+}
+
 func (ad *AssetOwnershipData) UnmarshallBinary(r io.Reader) error {
 
 	err := binary.Read(r, binary.LittleEndian, &ad.PublicKey)
@@ -539,7 +699,11 @@ func (pa *AssetPossessions) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != RespondAssets {
-			return errors.Errorf("Invalid header type, expected %d, found %d", RespondAssets, header.Type)
+			return ErrUnexpectedPacket{Expected: RespondAssets, Got: header.Type}
+		}
+
+		if len(*pa) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
 		}
 
 		var possessedAssetData AssetPossessionData
@@ -572,6 +736,10 @@ func (pa *AssetPossessions) UnmarshallFromReader(r io.Reader) error {
 	return nil
 }
 
+func (ad *AssetPossessionData) GetLeafDigest() ([32]byte, error) {
+	return getLeafDigest(ad)
+}
+
 func (ad *AssetPossessionData) UnmarshallBinary(r io.Reader) error {
 
 	err := binary.Read(r, binary.LittleEndian, &ad.PublicKey)
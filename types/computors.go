@@ -1,8 +1,10 @@
 package types
 
 import (
+	"bytes"
 	"encoding/binary"
 	"github.com/pkg/errors"
+	"github.com/qubic/go-schnorrq"
 	"io"
 )
 
@@ -21,17 +23,15 @@ type Computors struct {
 func (cs *Computors) UnmarshallFromReader(r io.Reader) error {
 	for {
 		var header RequestResponseHeader
-		headerSize := binary.Size(header)
 		err := binary.Read(r, binary.BigEndian, &header)
 		if err != nil {
 			return errors.Wrap(err, "reading header")
 		}
 
 		if header.Type != BroadcastComputors {
-			ignoredbytes := make([]byte, header.GetSize() - uint32(headerSize))
-			_, err := r.Read(ignoredbytes)
+			err := skipPayload(r, header)
 			if err != nil {
-				return errors.Wrap(err, "reading ignored bytes")
+				return errors.Wrap(err, "skipping ignored packet")
 			}
 			continue
 		}
@@ -45,4 +45,34 @@ func (cs *Computors) UnmarshallFromReader(r io.Reader) error {
 	}
 }
 
+// GetDigest returns the K12 digest of the computor list signed by the arbitrator.
+func (cs *Computors) GetDigest() ([32]byte, error) {
+	var buff bytes.Buffer
+	err := binary.Write(&buff, binary.LittleEndian, cs)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "serializing computors")
+	}
+
+	serialized := buff.Bytes()
+	digest, err := k12Hash(serialized[:len(serialized)-SignatureSize])
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "hashing computors")
+	}
+
+	return digest, nil
+}
 
+// Verify checks that the computor list is signed by the arbitrator, see ArbitratorIdentity.
+func (cs *Computors) Verify(arbitratorPubKey [32]byte) error {
+	digest, err := cs.GetDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting computors digest")
+	}
+
+	err = schnorrq.Verify(arbitratorPubKey, digest, cs.Signature)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "verifying arbitrator signature: %s", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/qubic/go-schnorrq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+const (
+	testArbitratorSeed = "yfcqxawkwvhnwwxnhxqbzufpnbxxvkpuueermpcxoiugqokwbmurqjq"
+	testComputorSeed   = "oqrtktmxmowfwpliikiogiczvpelmuaamreundljwnnpjojvtsfhtgd"
+)
+
+// signDigest signs a digest the same way a computor or the arbitrator does.
+func signDigest(t *testing.T, seed string, digest [32]byte) [64]byte {
+	wallet, err := NewWallet(seed)
+	require.NoError(t, err)
+
+	subSeed, err := GetSubSeed(seed)
+	require.NoError(t, err)
+
+	signature, err := schnorrq.Sign(subSeed, wallet.PubKey, digest)
+	require.NoError(t, err)
+
+	return signature
+}
+
+// newTestComputors returns a signed computor list with the test computor at index 5.
+func newTestComputors(t *testing.T) Computors {
+	computor, err := NewWallet(testComputorSeed)
+	require.NoError(t, err)
+
+	computors := Computors{Epoch: 150}
+	computors.PubKeys[5] = computor.PubKey
+
+	digest, err := computors.GetDigest()
+	require.NoError(t, err)
+	computors.Signature = signDigest(t, testArbitratorSeed, digest)
+
+	return computors
+}
+
+func TestComputors_Verify(t *testing.T) {
+	computors := newTestComputors(t)
+
+	arbitrator, err := NewWallet(testArbitratorSeed)
+	require.NoError(t, err)
+
+	err = computors.Verify(arbitrator.PubKey)
+	assert.NoError(t, err)
+
+	computors.PubKeys[6] = [32]byte{1}
+	err = computors.Verify(arbitrator.PubKey)
+	assert.Error(t, err, "tampered computor list must not verify")
+
+	computors = newTestComputors(t)
+	computor, err := NewWallet(testComputorSeed)
+	require.NoError(t, err)
+	err = computors.Verify(computor.PubKey)
+	assert.Error(t, err, "computor list must only verify against the arbitrator key")
+}
+
+func TestComputors_DigestLayout(t *testing.T) {
+	computors := newTestComputors(t)
+
+	// the signed bytes of a computor list in the core layout, the epoch followed by the public keys
+	signed := make([]byte, 2+32*NumberOfComputors)
+	binary.LittleEndian.PutUint16(signed[0:], 150)
+	copy(signed[2+32*5:], computors.PubKeys[5][:])
+	expected, err := k12Hash(signed)
+	require.NoError(t, err)
+
+	digest, err := computors.GetDigest()
+	require.NoError(t, err)
+	assert.Equal(t, expected, digest)
+	assert.Equal(t, "387252b21439a84886eaf899f82bc6c37b8dac288497370d8b80aa0a455860bc", hex.EncodeToString(digest[:]))
+
+	// signature of the test arbitrator over the digest
+	assert.Equal(t, "4a92d080ef4336149fb3e80718322240c4a7708ca6f647b36c2a759f2e43f609e54697d86ae45287daada31f57ea3df148e29dde31764fba45e3d67200381800", hex.EncodeToString(computors.Signature[:]))
+}
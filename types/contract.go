@@ -0,0 +1,180 @@
+package types
+
+import (
+	"encoding/binary"
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	QxContractIndex    = 1
+	QutilContractIndex = 4
+)
+
+// ContractPublicKey returns the public key of a contract, its index as a little endian number.
+func ContractPublicKey(contractIndex uint32) [32]byte {
+	var pubKey [32]byte
+	binary.LittleEndian.PutUint32(pubKey[:], contractIndex)
+
+	return pubKey
+}
+
+// ContractAddress returns the identity of a contract, e.g. QxAddress for QxContractIndex.
+func ContractAddress(contractIndex uint32) (Identity, error) {
+	var address Identity
+	address, err := address.FromPubKey(ContractPublicKey(contractIndex), false)
+	if err != nil {
+		return "", errors.Wrapf(err, "getting address of contract %d", contractIndex)
+	}
+
+	return address, nil
+}
+
+// NewContractProcedureTransaction creates a transaction calling the procedure inputType of a contract. The amount is
+// the invocation reward, which pays the fees of the procedure.
+func NewContractProcedureTransaction(sourceID string, contractIndex uint32, inputType uint16, input []byte, amount int64, targetTick uint32) (Transaction, error) {
+	sourceIdentity := Identity(sourceID)
+	sourcePublicKey, err := sourceIdentity.ToPubKey(false)
+	if err != nil {
+		return Transaction{}, errors.Wrap(err, "converting source id to public key")
+	}
+
+	if len(input) > MaxInputSize {
+		return Transaction{}, errors.Errorf("input of %d bytes is larger than %d", len(input), MaxInputSize)
+	}
+
+	return Transaction{
+		SourcePublicKey:      sourcePublicKey,
+		DestinationPublicKey: ContractPublicKey(contractIndex),
+		Amount:               amount,
+		Tick:                 targetTick,
+		InputType:            inputType,
+		InputSize:            uint16(len(input)),
+		Input:                input,
+	}, nil
+}
+
+// ContractInfo describes a contract deployed on the network.
+type ContractInfo struct {
+	Index   uint32
+	Name    string
+	Address Identity
+	// Procedures and Functions map names to input types, for the contracts whose interface is known.
+	Procedures map[string]uint16
+	Functions  map[string]uint16
+}
+
+var (
+	contractsMu sync.RWMutex
+	contracts   = make(map[uint32]ContractInfo)
+)
+
+// RegisterContract adds a contract to the registry, replacing the one with the same index if any. The address is
+// derived from the index when empty.
+func RegisterContract(info ContractInfo) error {
+	if info.Address == "" {
+		address, err := ContractAddress(info.Index)
+		if err != nil {
+			return err
+		}
+		info.Address = address
+	}
+
+	contractsMu.Lock()
+	defer contractsMu.Unlock()
+	contracts[info.Index] = info
+
+	return nil
+}
+
+func ContractByIndex(index uint32) (ContractInfo, bool) {
+	contractsMu.RLock()
+	defer contractsMu.RUnlock()
+
+	info, ok := contracts[index]
+	return info, ok
+}
+
+func ContractByName(name string) (ContractInfo, bool) {
+	contractsMu.RLock()
+	defer contractsMu.RUnlock()
+
+	for _, info := range contracts {
+		if info.Name == name {
+			return info, true
+		}
+	}
+
+	return ContractInfo{}, false
+}
+
+// ContractByAddress returns the contract a transaction is sent to, if any.
+func ContractByAddress(pubKey [32]byte) (ContractInfo, bool) {
+	index := binary.LittleEndian.Uint32(pubKey[:])
+	if pubKey != ContractPublicKey(index) {
+		return ContractInfo{}, false
+	}
+
+	return ContractByIndex(index)
+}
+
+// Contracts returns the registered contracts ordered by index.
+func Contracts() []ContractInfo {
+	contractsMu.RLock()
+	defer contractsMu.RUnlock()
+
+	list := make([]ContractInfo, 0, len(contracts))
+	for _, info := range contracts {
+		list = append(list, info)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Index < list[j].Index })
+
+	return list
+}
+
+func init() {
+	known := []ContractInfo{
+		{
+			Index: QxContractIndex,
+			Name:  "QX",
+			Procedures: map[string]uint16{
+				"IssueAsset":                          QxIssueAssetInputType,
+				"TransferShareOwnershipAndPossession": QxTransferInputType,
+				"AddToAskOrder":                       QxAddToAskOrderInputType,
+				"AddToBidOrder":                       QxAddToBidOrderInputType,
+				"RemoveFromAskOrder":                  QxRemoveFromAskOrderInputType,
+				"RemoveFromBidOrder":                  QxRemoveFromBidOrderInputType,
+			},
+			Functions: map[string]uint16{
+				"Fees":            1,
+				"AssetAskOrders":  2,
+				"AssetBidOrders":  3,
+				"EntityAskOrders": 4,
+				"EntityBidOrders": 5,
+			},
+		},
+		{Index: 2, Name: "QUOTTERY"},
+		{Index: 3, Name: "RANDOM"},
+		{
+			Index: QutilContractIndex,
+			Name:  "QUTIL",
+			Procedures: map[string]uint16{
+				"SendToManyV1": QutilSendManyInputType,
+			},
+		},
+		{Index: 5, Name: "MLM"},
+		{Index: 6, Name: "GQMPROP"},
+		{Index: 7, Name: "SWATCH"},
+		{Index: 8, Name: "CCF"},
+		{Index: 9, Name: "QEARN"},
+	}
+
+	for _, info := range known {
+		err := RegisterContract(info)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContractAddress(t *testing.T) {
+	qx, err := ContractAddress(QxContractIndex)
+	require.NoError(t, err)
+	assert.Equal(t, Identity(QxAddress), qx)
+
+	qutil, err := ContractAddress(QutilContractIndex)
+	require.NoError(t, err)
+	assert.Equal(t, Identity(QutilAddress), qutil)
+}
+
+func TestNewContractProcedureTransaction(t *testing.T) {
+	tx, err := NewContractProcedureTransaction(validationTestIdentity, 9, 3, []byte{1, 2}, 50, 1000)
+	require.NoError(t, err)
+	assert.Equal(t, ContractPublicKey(9), tx.DestinationPublicKey)
+	assert.Equal(t, uint16(3), tx.InputType)
+	assert.Equal(t, uint16(2), tx.InputSize)
+	assert.Equal(t, int64(50), tx.Amount)
+	assert.Equal(t, uint32(1000), tx.Tick)
+
+	_, err = NewContractProcedureTransaction(validationTestIdentity, 9, 3, make([]byte, MaxInputSize+1), 0, 1000)
+	require.Error(t, err)
+}
+
+func TestContractRegistry(t *testing.T) {
+	qx, ok := ContractByIndex(QxContractIndex)
+	require.True(t, ok)
+	assert.Equal(t, "QX", qx.Name)
+	assert.Equal(t, Identity(QxAddress), qx.Address)
+	assert.Equal(t, uint16(QxAddToBidOrderInputType), qx.Procedures["AddToBidOrder"])
+
+	qutil, ok := ContractByName("QUTIL")
+	require.True(t, ok)
+	assert.Equal(t, uint32(QutilContractIndex), qutil.Index)
+
+	byAddress, ok := ContractByAddress(ContractPublicKey(QutilContractIndex))
+	require.True(t, ok)
+	assert.Equal(t, "QUTIL", byAddress.Name)
+
+	_, ok = ContractByAddress([32]byte{4, 1})
+	assert.False(t, ok)
+
+	require.NoError(t, RegisterContract(ContractInfo{Index: 1000, Name: "TEST"}))
+	test, ok := ContractByName("TEST")
+	require.True(t, ok)
+	assert.Equal(t, ContractPublicKey(1000), mustContractPubKey(t, test.Address))
+	assert.Equal(t, uint32(1000), Contracts()[len(Contracts())-1].Index)
+}
+
+func mustContractPubKey(t *testing.T, address Identity) [32]byte {
+	pubKey, err := address.ToPubKey(false)
+	require.NoError(t, err)
+
+	return pubKey
+}
@@ -0,0 +1,79 @@
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Digest is a K12 digest, such as a transaction digest found in TickData.TransactionDigests. Its string form is the
+// lower case identity returned by Transaction.ID.
+type Digest [32]byte
+
+// ParseDigest parses a digest in lower case identity form, like a transaction id, or as 64 hex characters.
+func ParseDigest(s string) (Digest, error) {
+	s = strings.TrimSpace(s)
+
+	switch len(s) {
+	case 60:
+		id := Identity(s)
+		if !id.IsHash() {
+			return Digest{}, errors.Wrap(ErrInvalidIdentity, "digest must be lower case")
+		}
+
+		pubKey, err := id.PubKey()
+		if err != nil {
+			return Digest{}, errors.Wrap(err, "converting id to digest")
+		}
+
+		return pubKey, nil
+	case 2 * len(Digest{}):
+		var d Digest
+		_, err := hex.Decode(d[:], []byte(s))
+		if err != nil {
+			return Digest{}, errors.Wrap(err, "decoding hex digest")
+		}
+
+		return d, nil
+	default:
+		return Digest{}, errors.Errorf("digest must be a 60 letters id or 64 hex characters, got %d characters", len(s))
+	}
+}
+
+// String returns the digest in lower case identity form.
+func (d Digest) String() string {
+	var id Identity
+	id, err := id.FromPubKey(d, true)
+	if err != nil {
+		// hashing only fails on a broken k12 implementation
+		return d.Hex()
+	}
+
+	return id.String()
+}
+
+func (d Digest) Hex() string {
+	return hex.EncodeToString(d[:])
+}
+
+func (d Digest) IsZero() bool {
+	return d == Digest{}
+}
+
+// MarshalText encodes the digest in lower case identity form, which is also used by encoding/json.
+func (d Digest) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// UnmarshalText accepts both forms parsed by ParseDigest.
+func (d *Digest) UnmarshalText(text []byte) error {
+	parsed, err := ParseDigest(string(text))
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDigest(t *testing.T) {
+	tx, err := NewSimpleTransferTransaction(validationTestIdentity, validationTestDest, 10, 1000)
+	require.NoError(t, err)
+	digest, err := tx.Digest()
+	require.NoError(t, err)
+	id, err := tx.ID()
+	require.NoError(t, err)
+
+	assert.Equal(t, id, digest.String())
+
+	parsed, err := ParseDigest(" " + id + "\n")
+	require.NoError(t, err)
+	assert.Equal(t, digest, parsed)
+
+	parsed, err = ParseDigest(digest.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, digest, parsed)
+
+	_, err = ParseDigest(validationTestIdentity)
+	assert.True(t, errors.Is(err, ErrInvalidIdentity), "upper case identities are not digests")
+
+	// the first letter of the id is changed, failing the checksum
+	typo := []byte(id)
+	typo[0] = 'a' + (typo[0]-'a'+1)%26
+	_, err = ParseDigest(string(typo))
+	assert.True(t, errors.Is(err, ErrInvalidIdentity))
+
+	_, err = ParseDigest(id[1:])
+	assert.Error(t, err)
+
+	_, err = ParseDigest("zz" + digest.Hex()[2:])
+	assert.Error(t, err)
+}
+
+func TestDigest_JSON(t *testing.T) {
+	digest := Digest{1, 2, 3}
+
+	data, err := json.Marshal(struct{ TxDigest Digest }{digest})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"TxDigest":"`+digest.String()+`"}`, string(data))
+
+	var decoded struct{ TxDigest Digest }
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, digest, decoded.TxDigest)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"TxDigest":"`+digest.Hex()+`"}`), &decoded))
+	assert.Equal(t, digest, decoded.TxDigest)
+
+	assert.Error(t, json.Unmarshal([]byte(`{"TxDigest":"abc"}`), &decoded))
+}
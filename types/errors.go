@@ -0,0 +1,49 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	// ErrInvalidIdentity is returned for identities that are not 60 upper case letters, or 60 lower case letters for
+	// hashes.
+	ErrInvalidIdentity = errors.New("invalid identity")
+	// ErrEmptyTick is returned for ticks without tick data, either skipped by the network or not yet published, e.g.
+	// by Client.GetTickData.
+	ErrEmptyTick = errors.New("tick is empty")
+	// ErrInvalidSignature is returned when a signature does not match the signed data and public key.
+	ErrInvalidSignature = errors.New("invalid signature")
+	// ErrInvalidPacketSize is returned for packets announcing a size smaller than their header or larger than the
+	// maximum accepted.
+	ErrInvalidPacketSize = errors.New("invalid packet size")
+	// ErrTxNotSigned is returned when a transaction that must be signed has an empty signature.
+	ErrTxNotSigned = errors.New("transaction is not signed")
+	// ErrResponseTooLarge is returned when a response holds more packets than the protocol allows for it, or more data
+	// than the client buffers or reads for one response.
+	ErrResponseTooLarge = errors.New("response too large")
+)
+
+// ErrUnexpectedPacket is returned when a response decoder reads a packet of another type than the one it decodes.
+type ErrUnexpectedPacket struct {
+	Expected uint8
+	Got      uint8
+}
+
+func (e ErrUnexpectedPacket) Error() string {
+	return fmt.Sprintf("unexpected packet type %d, expected %d", e.Got, e.Expected)
+}
+
+// ErrInvalidTransaction is returned by ValidateTransaction, Err tells which check failed.
+type ErrInvalidTransaction struct {
+	Err error
+}
+
+func (e ErrInvalidTransaction) Error() string {
+	return "invalid transaction: " + e.Err.Error()
+}
+
+func (e ErrInvalidTransaction) Unwrap() error {
+	return e.Err
+}
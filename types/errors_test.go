@@ -0,0 +1,56 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrUnexpectedPacket(t *testing.T) {
+	packet := Packet{Header: RequestResponseHeader{Type: EndResponse}}
+
+	var tickInfo TickInfo
+	err := tickInfo.UnmarshallFromReader(bytes.NewReader(packet.Bytes()))
+
+	var packetErr ErrUnexpectedPacket
+	require.True(t, errors.As(errors.Wrap(err, "getting tick info"), &packetErr))
+	assert.Equal(t, ErrUnexpectedPacket{Expected: CurrentTickInfoResponse, Got: EndResponse}, packetErr)
+}
+
+func TestErrInvalidIdentity(t *testing.T) {
+	identity := Identity("TOOSHORT")
+	_, err := identity.ToPubKey(false)
+	assert.True(t, errors.Is(err, ErrInvalidIdentity))
+}
+
+func TestErrInvalidTransaction(t *testing.T) {
+	tx, err := NewSimpleTransferTransaction(validationTestIdentity, validationTestDest, 1, 1000)
+	require.NoError(t, err)
+
+	err = ValidateTransaction(tx, ValidationOptions{})
+	var txErr ErrInvalidTransaction
+	require.True(t, errors.As(err, &txErr))
+	assert.True(t, errors.Is(err, ErrTxNotSigned))
+
+	tx = signValidationTestTx(t, tx)
+	tx.Amount = 2
+	err = ValidateTransaction(tx, ValidationOptions{})
+	assert.True(t, errors.Is(err, ErrInvalidSignature))
+}
+
+func TestErrResponseTooLarge(t *testing.T) {
+	var buff bytes.Buffer
+	for i := 0; i <= NumberOfComputors; i++ {
+		vote := Packet{Header: RequestResponseHeader{Type: QuorumTickResponse}, Payload: make([]byte, binary.Size(QuorumTickVote{}))}
+		buff.Write(vote.Bytes())
+	}
+
+	var votes QuorumVotes
+	err := votes.UnmarshallFromReader(&buff)
+	assert.True(t, errors.Is(err, ErrResponseTooLarge))
+	assert.Len(t, votes, NumberOfComputors)
+}
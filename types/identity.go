@@ -2,10 +2,9 @@ package types
 
 import (
 	"encoding/binary"
-	"fmt"
 	"github.com/cloudflare/circl/xof/k12"
 	"github.com/pkg/errors"
-	"unicode"
+	"strings"
 )
 
 const ArbitratorIdentity = "AFZPUAIYVPNUYGJRQVLUKOPPVLHAZQTGLYAAUUNBXFTVTAMSBKQBLEIEPCVJ"
@@ -64,19 +63,19 @@ func (i *Identity) ToPubKey(isLowerCase bool) ([32]byte, error) {
 	var pubKey [32]byte
 
 	if !isValidIdFormat(string(*i)) {
-		return [32]byte{}, fmt.Errorf("invalid ID format")
+		return [32]byte{}, errors.Wrap(ErrInvalidIdentity, "invalid ID format")
 	}
 
 	idBytes := []byte(string(*i))
 
 	if len(idBytes) != 60 {
-		return [32]byte{}, fmt.Errorf("invalid ID length, expected 60, found %d", len(idBytes))
+		return [32]byte{}, errors.Wrapf(ErrInvalidIdentity, "invalid ID length, expected 60, found %d", len(idBytes))
 	}
 
 	for i := 0; i < 4; i++ {
 		for j := 13; j >= 0; j-- {
 			if idBytes[i * 14 + j] < letters[0] || idBytes[i * 14 + j] > letters[1] {
-				return [32]byte{}, errors.New( "invalid conversion")
+				return [32]byte{}, errors.Wrap(ErrInvalidIdentity, "invalid conversion")
 			}
 
 			im := binary.LittleEndian.Uint64(pubKey[i*8 : (i+1)*8])
@@ -90,9 +89,62 @@ func (i *Identity) ToPubKey(isLowerCase bool) ([32]byte, error) {
 		}
 	}
 
+	// the checksum letters are not part of the key, encoding the key back checks them and catches overflowing groups
+	expected, err := i.FromPubKey(pubKey, isLowerCase)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "computing checksum")
+	}
+	if expected != *i {
+		return [32]byte{}, errors.Wrap(ErrInvalidIdentity, "invalid checksum")
+	}
+
 	return pubKey, nil
 }
 
+// Validate checks the length, the case and the checksum of the identity. Both upper case identities and lower case
+// hashes, such as transaction ids, are valid.
+func (i *Identity) Validate() error {
+	_, err := i.PubKey()
+	return err
+}
+
+// IsHash reports whether the identity is in the lower case form used for hashes like transaction ids rather than
+// for public keys. It doesn't validate the identity.
+func (i *Identity) IsHash() bool {
+	return len(*i) == 60 && strings.ToLower(string(*i)) == string(*i)
+}
+
+// PubKey decodes the identity, or the hash in lower case form, into its 32 bytes.
+func (i *Identity) PubKey() ([32]byte, error) {
+	return i.ToPubKey(i.IsHash())
+}
+
+// ParseIdentity parses the identity of an entity or a contract, e.g. pasted by a user. Surrounding spaces are
+// ignored, hashes in lower case form are rejected.
+func ParseIdentity(s string) (Identity, error) {
+	identity := Identity(strings.TrimSpace(s))
+	if identity.IsHash() {
+		return "", errors.Wrap(ErrInvalidIdentity, "lower case hash instead of an identity")
+	}
+
+	err := identity.Validate()
+	if err != nil {
+		return "", err
+	}
+
+	return identity, nil
+}
+
+// MustIdentity is like ParseIdentity but panics on invalid identities. It is meant for constants.
+func MustIdentity(s string) Identity {
+	identity, err := ParseIdentity(s)
+	if err != nil {
+		panic(errors.Wrapf(err, "parsing identity %q", s))
+	}
+
+	return identity
+}
+
 func (i *Identity) String() string {
 	if i == nil {
 		return ""
@@ -100,10 +152,11 @@ func (i *Identity) String() string {
 	return string(*i)
 }
 
-// isValidIdFormat checks if the provided string has a valid ID format.
+// isValidIdFormat checks if the provided string has a valid ID format, ASCII letters only.
 func isValidIdFormat(idStr string) bool {
-	for _, c := range idStr {
-		if !unicode.IsLetter(c) {
+	for i := 0; i < len(idStr); i++ {
+		c := idStr[i]
+		if (c < 'A' || c > 'Z') && (c < 'a' || c > 'z') {
 			return false
 		}
 	}
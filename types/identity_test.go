@@ -3,6 +3,9 @@ package types
 import (
 	"encoding/hex"
 	"github.com/google/go-cmp/cmp"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"strings"
 	"testing"
 )
@@ -67,3 +70,60 @@ func TestGetPubKeyFromIdentityLowercase(t *testing.T) {
 		t.Fatalf("Mismatched return value. Expected: %s, got: %s", hex.EncodeToString(expectedPubKey[:]), hex.EncodeToString(got[:]))
 	}
 }
+
+func TestIdentity_Validate(t *testing.T) {
+	valid := []Identity{
+		"QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB",
+		"zycobqjpgdcagflcvgtkboafbryahgjbbwhgjjlblhzocwncjhhjshqfsndh",
+		QxAddress,
+	}
+	for _, identity := range valid {
+		assert.NoError(t, identity.Validate(), identity)
+	}
+
+	invalid := []Identity{
+		// typo in the first letter
+		"PJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB",
+		// typo in the checksum
+		"QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEC",
+		"QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJME",
+		"qJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB",
+		"ÄJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJME",
+		"ZZZZZZZZZZZZZZAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA",
+		"",
+	}
+	for _, identity := range invalid {
+		err := identity.Validate()
+		assert.True(t, errors.Is(err, ErrInvalidIdentity), "%q: %v", identity, err)
+	}
+}
+
+func TestIdentity_IsHash(t *testing.T) {
+	hash := Identity("zycobqjpgdcagflcvgtkboafbryahgjbbwhgjjlblhzocwncjhhjshqfsndh")
+	assert.True(t, hash.IsHash())
+
+	identity := Identity("QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB")
+	assert.False(t, identity.IsHash())
+}
+
+func TestIdentity_PubKey(t *testing.T) {
+	hash := Identity("zycobqjpgdcagflcvgtkboafbryahgjbbwhgjjlblhzocwncjhhjshqfsndh")
+	pubKey, err := hash.PubKey()
+	require.NoError(t, err)
+	assert.Equal(t, [32]byte{209, 173, 239, 194, 151, 98, 29, 180, 83, 67, 142, 32, 4, 9, 167, 32, 159, 95, 116, 116, 214, 221, 171, 255, 13, 125, 86, 112, 5, 31, 191, 193}, pubKey)
+}
+
+func TestParseIdentity(t *testing.T) {
+	identity, err := ParseIdentity("  QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB\n")
+	require.NoError(t, err)
+	assert.Equal(t, Identity("QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB"), identity)
+
+	_, err = ParseIdentity("zycobqjpgdcagflcvgtkboafbryahgjbbwhgjjlblhzocwncjhhjshqfsndh")
+	assert.True(t, errors.Is(err, ErrInvalidIdentity), "hashes are not identities")
+
+	_, err = ParseIdentity("QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEC")
+	assert.True(t, errors.Is(err, ErrInvalidIdentity))
+
+	assert.Equal(t, Identity(QutilAddress), MustIdentity(QutilAddress))
+	assert.Panics(t, func() { MustIdentity("QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEC") })
+}
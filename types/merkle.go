@@ -0,0 +1,73 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/pkg/errors"
+)
+
+// getMerkleRoot rebuilds the root of a K12 merkle tree from a leaf digest, its index and the sibling digests from
+// the leaf level upwards, the same way the node builds the spectrum and universe digests.
+func getMerkleRoot(leaf [32]byte, index uint32, siblings [][32]byte) ([32]byte, error) {
+	root := leaf
+	for _, sibling := range siblings {
+		var pair [64]byte
+		if index&1 == 0 {
+			copy(pair[:32], root[:])
+			copy(pair[32:], sibling[:])
+		} else {
+			copy(pair[:32], sibling[:])
+			copy(pair[32:], root[:])
+		}
+
+		var err error
+		root, err = k12Hash(pair[:])
+		if err != nil {
+			return [32]byte{}, errors.Wrap(err, "hashing merkle node")
+		}
+		index >>= 1
+	}
+
+	return root, nil
+}
+
+// SaltDigest returns the digest salted with a computor public key, as found in the Salted digest fields of a
+// QuorumTickVote.
+func SaltDigest(digest [32]byte, computorPubKey [32]byte) ([32]byte, error) {
+	var salted [64]byte
+	copy(salted[:32], computorPubKey[:])
+	copy(salted[32:], digest[:])
+
+	return k12Hash(salted[:])
+}
+
+func getLeafDigest(record interface{}) ([32]byte, error) {
+	var buff bytes.Buffer
+	err := binary.Write(&buff, binary.LittleEndian, record)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "serializing record")
+	}
+
+	return k12Hash(buff.Bytes())
+}
+
+func verifyRoot(root, expected [32]byte) error {
+	if root != expected {
+		return errors.Errorf("merkle root %x does not match expected digest %x", root, expected)
+	}
+
+	return nil
+}
+
+func verifySaltedRoot(root, saltedDigest, computorPubKey [32]byte) error {
+	salted, err := SaltDigest(root, computorPubKey)
+	if err != nil {
+		return errors.Wrap(err, "salting merkle root")
+	}
+
+	if salted != saltedDigest {
+		return errors.Errorf("salted merkle root %x does not match expected digest %x", salted, saltedDigest)
+	}
+
+	return nil
+}
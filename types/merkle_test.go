@@ -0,0 +1,108 @@
+package types
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+// buildRoot hashes a leaf up to the root level by level, independently of getMerkleRoot.
+func buildRoot(t *testing.T, leaf [32]byte, index uint32, siblings [][32]byte) [32]byte {
+	node := leaf
+	for level, sibling := range siblings {
+		var pair []byte
+		if (index>>level)%2 == 0 {
+			pair = append(append(pair, node[:]...), sibling[:]...)
+		} else {
+			pair = append(append(pair, sibling[:]...), node[:]...)
+		}
+
+		var err error
+		node, err = k12Hash(pair)
+		require.NoError(t, err)
+	}
+
+	return node
+}
+
+func TestAddressInfo_VerifyAgainst(t *testing.T) {
+	addressInfo := AddressInfo{
+		AddressData: AddressData{
+			PublicKey:                 [32]byte{1, 2, 3},
+			IncomingAmount:            5000,
+			OutgoingAmount:            1000,
+			NumberOfIncomingTransfers: 3,
+		},
+		Tick:          20200000,
+		SpectrumIndex: 0b101101,
+	}
+	for i := range addressInfo.Siblings {
+		addressInfo.Siblings[i] = [32]byte{byte(i + 1)}
+	}
+
+	leaf, err := getLeafDigest(addressInfo.AddressData)
+	require.NoError(t, err)
+	spectrumDigest := buildRoot(t, leaf, uint32(addressInfo.SpectrumIndex), addressInfo.Siblings[:])
+
+	err = addressInfo.VerifyAgainst(spectrumDigest)
+	assert.NoError(t, err)
+
+	computorPubKey := [32]byte{9}
+	salted, err := SaltDigest(spectrumDigest, computorPubKey)
+	require.NoError(t, err)
+	err = addressInfo.VerifyAgainstSalted(salted, computorPubKey)
+	assert.NoError(t, err)
+	err = addressInfo.VerifyAgainstSalted(spectrumDigest, computorPubKey)
+	assert.Error(t, err)
+
+	tampered := addressInfo
+	tampered.AddressData.IncomingAmount = 6000
+	err = tampered.VerifyAgainst(spectrumDigest)
+	assert.Error(t, err, "a reported balance that differs from the proven one must not verify")
+
+	wrongIndex := addressInfo
+	wrongIndex.SpectrumIndex = 0b101100
+	err = wrongIndex.VerifyAgainst(spectrumDigest)
+	assert.Error(t, err)
+
+	notInSpectrum := addressInfo
+	notInSpectrum.SpectrumIndex = -1
+	err = notInSpectrum.VerifyAgainst(spectrumDigest)
+	assert.Error(t, err)
+}
+
+func TestOwnedAsset_VerifyAgainst(t *testing.T) {
+	ownership := AssetOwnershipData{
+		PublicKey:             [32]byte{1},
+		Type:                  2,
+		ManagingContractIndex: 1,
+		IssuanceIndex:         7,
+		NumberOfUnits:         100,
+	}
+	asset := OwnedAsset{
+		Data: OwnedAssetData{
+			PublicKey:             ownership.PublicKey,
+			Type:                  ownership.Type,
+			ManagingContractIndex: ownership.ManagingContractIndex,
+			IssuanceIndex:         ownership.IssuanceIndex,
+			NumberOfUnits:         ownership.NumberOfUnits,
+			IssuedAsset:           IssuedAssetData{Type: 1, Name: [7]int8{'Q', 'X'}},
+		},
+		Info: AssetInfo{Tick: 20200000, UniverseIndex: 16697282},
+	}
+	for i := range asset.Info.Siblings {
+		asset.Info.Siblings[i] = [32]byte{byte(100 + i)}
+	}
+
+	// only the 48 byte ownership record is part of the universe
+	leaf, err := getLeafDigest(ownership)
+	require.NoError(t, err)
+	universeDigest := buildRoot(t, leaf, asset.Info.UniverseIndex, asset.Info.Siblings[:])
+
+	err = asset.VerifyAgainst(universeDigest)
+	assert.NoError(t, err)
+
+	asset.Data.NumberOfUnits = 101
+	err = asset.VerifyAgainst(universeDigest)
+	assert.Error(t, err)
+}
@@ -0,0 +1,225 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Packet is a single raw message exchanged with a node. As a ReaderUnmarshaler it decodes any response type, which
+// makes it possible to call message types this library has no typed support for yet.
+type Packet struct {
+	Header  RequestResponseHeader
+	Payload []byte
+	// Data holds the payload decoded by the decoder registered for Header.Type. It is nil when there is none.
+	Data interface{}
+	// DecodeErr is set instead of Data on packets delivered to subscriptions when decoding the payload failed.
+	DecodeErr error
+}
+
+// PacketDecoder decodes the payload of a packet into its typed struct.
+type PacketDecoder func(p Packet) (interface{}, error)
+
+var (
+	decodersMu sync.RWMutex
+	decoders   = make(map[uint8]PacketDecoder)
+)
+
+// RegisterPacketDecoder sets the decoder used for packets of the given type, replacing the existing one if any.
+func RegisterPacketDecoder(packetType uint8, decoder PacketDecoder) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[packetType] = decoder
+}
+
+// DecodePacket decodes the payload with the decoder registered for the packet type. Packet types without a decoder
+// decode to nil.
+func DecodePacket(p Packet) (interface{}, error) {
+	decodersMu.RLock()
+	decoder, ok := decoders[p.Header.Type]
+	decodersMu.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	data, err := decoder(p)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding packet type %d", p.Header.Type)
+	}
+
+	return data, nil
+}
+
+// BroadcastTypes are the packet types nodes push without being asked.
+var BroadcastTypes = []uint8{
+	ExchangePublicPeers,
+	BroadcastComputors,
+	QuorumTickResponse,
+	BroadcastFutureTickData,
+	BroadcastTransaction,
+}
+
+func init() {
+	RegisterPacketDecoder(ExchangePublicPeers, func(p Packet) (interface{}, error) {
+		var peers PublicPeers
+		err := peers.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return peers, err
+	})
+	RegisterPacketDecoder(BroadcastComputors, func(p Packet) (interface{}, error) {
+		var computors Computors
+		err := computors.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return computors, err
+	})
+	RegisterPacketDecoder(QuorumTickResponse, func(p Packet) (interface{}, error) {
+		var vote QuorumTickVote
+		err := binary.Read(bytes.NewReader(p.Payload), binary.LittleEndian, &vote)
+		return vote, err
+	})
+	RegisterPacketDecoder(BroadcastFutureTickData, func(p Packet) (interface{}, error) {
+		var tickData TickData
+		err := tickData.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return tickData, err
+	})
+	RegisterPacketDecoder(BroadcastTransaction, func(p Packet) (interface{}, error) {
+		var tx Transaction
+		err := tx.UnmarshallBinary(bytes.NewReader(p.Payload))
+		return tx, err
+	})
+	RegisterPacketDecoder(CurrentTickInfoResponse, func(p Packet) (interface{}, error) {
+		var tickInfo TickInfo
+		err := tickInfo.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return tickInfo, err
+	})
+	RegisterPacketDecoder(BalanceTypeResponse, func(p Packet) (interface{}, error) {
+		var addressInfo AddressInfo
+		err := addressInfo.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return addressInfo, err
+	})
+	RegisterPacketDecoder(TxStatusResponse, func(p Packet) (interface{}, error) {
+		var txStatus TransactionStatus
+		err := txStatus.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return txStatus, err
+	})
+	RegisterPacketDecoder(SystemInfoResponse, func(p Packet) (interface{}, error) {
+		var systemInfo SystemInfo
+		err := systemInfo.UnmarshallFromReader(bytes.NewReader(p.Bytes()))
+		return systemInfo, err
+	})
+}
+
+// MaxPacketSize is the largest packet ReadPacket accepts from a node. The largest responses, tick data and contract
+// function outputs, are a few tens of kilobytes.
+const MaxPacketSize = 1 << 20
+
+// MaxResponseSize is the most bytes a response may span over all its packets. The largest responses of the protocol,
+// asset listings of about 800 bytes per record, stay well below it.
+const MaxResponseSize = 64 << 20
+
+// MaxPacketsPerResponse caps the packets of a response read into Packets or the asset decoders, so that a node can't
+// make the decoded response grow far beyond MaxResponseSize with tiny packets. Assets are held by a few ten thousand
+// identities at most.
+const MaxPacketsPerResponse = 1 << 17
+
+// errTooManyPackets is returned by the decoders of responses made of several packets once more than max packets were
+// read, so that a node can't grow a response without end.
+func errTooManyPackets(max int) error {
+	return errors.Wrapf(ErrResponseTooLarge, "more than %d packets", max)
+}
+
+// ReadPacket reads exactly one packet from r. The size announced in the header is checked against maxSize before the
+// payload is allocated, the stream can't be trusted after an invalid size so the caller should drop it.
+func ReadPacket(r io.Reader, maxSize int) (Packet, error) {
+	var header RequestResponseHeader
+	err := binary.Read(r, binary.LittleEndian, &header)
+	if err != nil {
+		return Packet{}, errors.Wrap(err, "reading header")
+	}
+
+	payloadSize, err := header.GetPayloadSize()
+	if err != nil {
+		return Packet{}, err
+	}
+
+	if int(header.GetSize()) > maxSize {
+		return Packet{}, errors.Wrapf(ErrInvalidPacketSize, "size %d exceeds the maximum of %d", header.GetSize(), maxSize)
+	}
+
+	payload := make([]byte, payloadSize)
+	_, err = io.ReadFull(r, payload)
+	if err != nil {
+		return Packet{}, errors.Wrap(err, "reading payload")
+	}
+
+	return Packet{Header: header, Payload: payload}, nil
+}
+
+// UnmarshallFromReader reads exactly one packet and decodes its payload into Data if a decoder is registered.
+func (p *Packet) UnmarshallFromReader(r io.Reader) error {
+	packet, err := ReadPacket(r, MaxPacketSize)
+	if err != nil {
+		return err
+	}
+
+	*p = packet
+	p.Data, err = DecodePacket(*p)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// skipPayload discards the payload of a packet whose header was already read.
+func skipPayload(r io.Reader, header RequestResponseHeader) error {
+	payloadSize, err := header.GetPayloadSize()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(io.Discard, r, int64(payloadSize))
+	if err != nil {
+		return errors.Wrap(err, "skipping payload")
+	}
+
+	return nil
+}
+
+// Packets collects the packets of a response made of several packets, up to the closing EndResponse which is not
+// included.
+type Packets []Packet
+
+func (ps *Packets) UnmarshallFromReader(r io.Reader) error {
+	for {
+		var p Packet
+		err := p.UnmarshallFromReader(r)
+		if err != nil {
+			return err
+		}
+
+		if p.Header.Type == EndResponse {
+			return nil
+		}
+
+		if len(*ps) >= MaxPacketsPerResponse {
+			return errTooManyPackets(MaxPacketsPerResponse)
+		}
+
+		*ps = append(*ps, p)
+	}
+}
+
+// Bytes returns the packet as it is sent on the wire, header included.
+func (p *Packet) Bytes() []byte {
+	header := p.Header
+	header.SetSize(uint32(binary.Size(header) + len(p.Payload)))
+
+	var buff bytes.Buffer
+	binary.Write(&buff, binary.LittleEndian, header)
+	buff.Write(p.Payload)
+
+	return buff.Bytes()
+}
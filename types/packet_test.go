@@ -0,0 +1,130 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPacket_UnmarshallFromReader(t *testing.T) {
+	tickInfo := TickInfo{Epoch: 150, Tick: 1000, InitialTick: 900}
+	var payload bytes.Buffer
+	require.NoError(t, binary.Write(&payload, binary.LittleEndian, tickInfo))
+
+	sent := Packet{Header: RequestResponseHeader{Type: CurrentTickInfoResponse, DejaVu: 0x01020304}, Payload: payload.Bytes()}
+	raw := sent.Bytes()
+
+	var got Packet
+	err := got.UnmarshallFromReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	assert.Equal(t, uint8(CurrentTickInfoResponse), got.Header.Type)
+	assert.Equal(t, uint32(0x01020304), got.Header.DejaVu)
+	assert.Equal(t, payload.Bytes(), got.Payload)
+	assert.Equal(t, tickInfo, got.Data)
+
+	err = got.UnmarshallFromReader(bytes.NewReader(raw[:len(raw)-1]))
+	assert.Error(t, err)
+}
+
+func TestPacket_RegisterPacketDecoder(t *testing.T) {
+	const unknownType = 250
+
+	sent := Packet{Header: RequestResponseHeader{Type: unknownType}, Payload: []byte{1, 2, 3}}
+	data, err := DecodePacket(sent)
+	require.NoError(t, err)
+	assert.Nil(t, data)
+
+	RegisterPacketDecoder(unknownType, func(p Packet) (interface{}, error) {
+		return len(p.Payload), nil
+	})
+	defer func() {
+		decodersMu.Lock()
+		delete(decoders, unknownType)
+		decodersMu.Unlock()
+	}()
+
+	var packets Packets
+	raw := append(sent.Bytes(), sent.Bytes()...)
+	end := Packet{Header: RequestResponseHeader{Type: EndResponse}}
+	raw = append(raw, end.Bytes()...)
+	err = packets.UnmarshallFromReader(bytes.NewReader(raw))
+	require.NoError(t, err)
+	require.Len(t, packets, 2)
+	assert.Equal(t, 3, packets[0].Data)
+	assert.Equal(t, []byte{1, 2, 3}, packets[1].Payload)
+}
+
+func TestReadPacket(t *testing.T) {
+	sent := Packet{Header: RequestResponseHeader{Type: EndResponse, DejaVu: 7}, Payload: []byte{1, 2, 3}}
+
+	got, err := ReadPacket(bytes.NewReader(sent.Bytes()), MaxPacketSize)
+	require.NoError(t, err)
+	assert.Equal(t, sent.Header.DejaVu, got.Header.DejaVu)
+	assert.Equal(t, sent.Payload, got.Payload)
+
+	_, err = ReadPacket(bytes.NewReader(sent.Bytes()), 10)
+	require.Error(t, err, "larger than max size")
+
+	_, err = ReadPacket(bytes.NewReader(sent.Bytes()[:10]), MaxPacketSize)
+	require.Error(t, err, "truncated payload")
+
+	var header RequestResponseHeader
+	header.SetSize(4)
+	var raw bytes.Buffer
+	require.NoError(t, binary.Write(&raw, binary.LittleEndian, header))
+	_, err = ReadPacket(&raw, MaxPacketSize)
+	require.Error(t, err, "size smaller than header")
+}
+
+func TestDecoders_InvalidSize(t *testing.T) {
+	var header RequestResponseHeader
+	header.SetSize(4)
+	header.Type = ContractFunctionResponse
+	var raw bytes.Buffer
+	require.NoError(t, binary.Write(&raw, binary.LittleEndian, header))
+
+	var scData SmartContractData
+	require.Error(t, scData.UnmarshallFromReader(bytes.NewReader(raw.Bytes())))
+
+	// ignored packets are skipped by size, a size smaller than the header can't be skipped
+	raw.Bytes()[3] = ExchangePublicPeers
+	var tickInfo TickInfo
+	require.Error(t, tickInfo.UnmarshallFromReader(bytes.NewReader(raw.Bytes())))
+}
+
+func TestTickInfo_SkipsIgnoredPacket(t *testing.T) {
+	peers := Packet{Header: RequestResponseHeader{Type: ExchangePublicPeers}, Payload: make([]byte, 16)}
+	tickInfo := TickInfo{Epoch: 150, Tick: 1000, InitialTick: 900}
+	var payload bytes.Buffer
+	require.NoError(t, binary.Write(&payload, binary.LittleEndian, tickInfo))
+	response := Packet{Header: RequestResponseHeader{Type: CurrentTickInfoResponse}, Payload: payload.Bytes()}
+
+	// a reader returning one byte at a time, a single Read could not skip the whole ignored payload
+	stream := &oneByteReader{data: append(peers.Bytes(), response.Bytes()...)}
+
+	var got TickInfo
+	require.NoError(t, got.UnmarshallFromReader(stream))
+	assert.Equal(t, tickInfo, got)
+}
+
+type oneByteReader struct {
+	data []byte
+}
+
+func (r *oneByteReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, io.EOF
+	}
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	p[0] = r.data[0]
+	r.data = r.data[1:]
+
+	return 1, nil
+}
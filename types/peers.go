@@ -17,7 +17,7 @@ func (pp *PublicPeers) UnmarshallFromReader(r io.Reader) error {
 	}
 
 	if header.Type != ExchangePublicPeers {
-		return errors.Errorf("Invalid header type, expected %d, found %d", ExchangePublicPeers, header.Type)
+		return ErrUnexpectedPacket{Expected: ExchangePublicPeers, Got: header.Type}
 	}
 
 	var peers [4][4]byte
@@ -39,18 +39,6 @@ func (pp *PublicPeers) UnmarshallFromReader(r io.Reader) error {
 		*pp = append(*pp, ip.String())
 	}
 
-	var nextHeader RequestResponseHeader
-	err = binary.Read(r, binary.BigEndian, &nextHeader)
-	if err != nil {
-		return errors.Wrap(err, "reading header")
-	}
-
-	ignoredBytes := make([]byte, nextHeader.GetSize()-uint32(binary.Size(nextHeader)))
-	_, err = r.Read(ignoredBytes)
-	if err != nil {
-		return errors.Wrap(err, "reading ignored bytes")
-	}
-
 	return nil
 }
 
@@ -1,8 +1,10 @@
 package types
 
 import (
+	"bytes"
 	"encoding/binary"
 	"github.com/pkg/errors"
+	"github.com/qubic/go-schnorrq"
 	"io"
 )
 
@@ -29,16 +31,16 @@ type QuorumTickVote struct {
 	PreviousTransactionBodyDigest uint32
 	SaltedTransactionBodyDigest   uint32
 
-	PreviousSpectrumDigest [32]byte
-	PreviousUniverseDigest [32]byte
-	PreviousComputerDigest [32]byte
+	PreviousSpectrumDigest Digest
+	PreviousUniverseDigest Digest
+	PreviousComputerDigest Digest
 
-	SaltedSpectrumDigest [32]byte
-	SaltedUniverseDigest [32]byte
-	SaltedComputerDigest [32]byte
+	SaltedSpectrumDigest Digest
+	SaltedUniverseDigest Digest
+	SaltedComputerDigest Digest
 
-	TxDigest                 [32]byte
-	ExpectedNextTickTxDigest [32]byte
+	TxDigest                 Digest
+	ExpectedNextTickTxDigest Digest
 
 	Signature [SignatureSize]byte
 }
@@ -59,7 +61,11 @@ func (qv *QuorumVotes) UnmarshallFromReader(r io.Reader) error {
 
 		var qtd QuorumTickVote
 		if header.Type != QuorumTickResponse {
-			return errors.Errorf("Invalid header type, expected %d, found %d", QuorumTickResponse, header.Type)
+			return ErrUnexpectedPacket{Expected: QuorumTickResponse, Got: header.Type}
+		}
+
+		if len(*qv) >= NumberOfComputors {
+			return errTooManyPackets(NumberOfComputors)
 		}
 
 		err = binary.Read(r, binary.LittleEndian, &qtd)
@@ -72,3 +78,47 @@ func (qv *QuorumVotes) UnmarshallFromReader(r io.Reader) error {
 
 	return nil
 }
+
+// GetDigest returns the K12 digest signed by the computor. As the node does, the computor index is xor-ed with the
+// packet type before hashing.
+func (qtv *QuorumTickVote) GetDigest() ([32]byte, error) {
+	vote := *qtv
+	vote.ComputorIndex ^= QuorumTickResponse
+
+	var buff bytes.Buffer
+	err := binary.Write(&buff, binary.LittleEndian, vote)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "serializing quorum tick vote")
+	}
+
+	serialized := buff.Bytes()
+	digest, err := k12Hash(serialized[:len(serialized)-SignatureSize])
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "hashing quorum tick vote")
+	}
+
+	return digest, nil
+}
+
+// Verify checks that the vote is signed by the computor at ComputorIndex in the given computor list.
+func (qtv *QuorumTickVote) Verify(computors Computors) error {
+	if qtv.Epoch != computors.Epoch {
+		return errors.Errorf("vote epoch %d does not match computors epoch %d", qtv.Epoch, computors.Epoch)
+	}
+
+	if qtv.ComputorIndex >= NumberOfComputors {
+		return errors.Errorf("invalid computor index %d", qtv.ComputorIndex)
+	}
+
+	digest, err := qtv.GetDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting vote digest")
+	}
+
+	err = schnorrq.Verify(computors.PubKeys[qtv.ComputorIndex], digest, qtv.Signature)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "verifying signature of computor %d: %s", qtv.ComputorIndex, err)
+	}
+
+	return nil
+}
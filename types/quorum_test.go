@@ -0,0 +1,140 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestQuorumTickVote_Verify(t *testing.T) {
+	computors := newTestComputors(t)
+
+	vote := QuorumTickVote{
+		ComputorIndex:          5,
+		Epoch:                  150,
+		Tick:                   20200000,
+		PreviousSpectrumDigest: [32]byte{1, 2, 3},
+		TxDigest:               [32]byte{4, 5, 6},
+	}
+	digest, err := vote.GetDigest()
+	require.NoError(t, err)
+	vote.Signature = signDigest(t, testComputorSeed, digest)
+
+	err = vote.Verify(computors)
+	assert.NoError(t, err)
+
+	tampered := vote
+	tampered.TxDigest = [32]byte{7}
+	err = tampered.Verify(computors)
+	assert.Error(t, err)
+
+	wrongComputor := vote
+	wrongComputor.ComputorIndex = 6
+	err = wrongComputor.Verify(computors)
+	assert.Error(t, err)
+
+	wrongEpoch := vote
+	wrongEpoch.Epoch = 151
+	err = wrongEpoch.Verify(computors)
+	assert.Error(t, err)
+
+	outOfRange := vote
+	outOfRange.ComputorIndex = NumberOfComputors
+	err = outOfRange.Verify(computors)
+	assert.Error(t, err)
+}
+
+// newTestVectorVote returns a vote with a distinct value in every field, so that a field moved in the digest layout
+// changes the digest.
+func newTestVectorVote() QuorumTickVote {
+	vote := QuorumTickVote{
+		ComputorIndex:                 5,
+		Epoch:                         150,
+		Tick:                          20200000,
+		Millisecond:                   250,
+		Second:                        12,
+		Minute:                        30,
+		Hour:                          14,
+		Day:                           3,
+		Month:                         7,
+		Year:                          24,
+		PreviousResourceTestingDigest: 0x01020304,
+		SaltedResourceTestingDigest:   0x05060708,
+		PreviousTransactionBodyDigest: 0x090a0b0c,
+		SaltedTransactionBodyDigest:   0x0d0e0f10,
+	}
+	for i, digest := range vote.digests() {
+		digest[0] = byte(i + 1)
+		digest[31] = byte(0x80 | i)
+	}
+
+	return vote
+}
+
+func (qtv *QuorumTickVote) digests() []*Digest {
+	return []*Digest{
+		&qtv.PreviousSpectrumDigest, &qtv.PreviousUniverseDigest, &qtv.PreviousComputerDigest,
+		&qtv.SaltedSpectrumDigest, &qtv.SaltedUniverseDigest, &qtv.SaltedComputerDigest,
+		&qtv.TxDigest, &qtv.ExpectedNextTickTxDigest,
+	}
+}
+
+func TestQuorumTickVote_DigestLayout(t *testing.T) {
+	vote := newTestVectorVote()
+
+	// the signed bytes of a vote in the core layout, the computor index xor-ed with QUORUM_TICK_RESPONSE
+	signed := make([]byte, 288)
+	binary.LittleEndian.PutUint16(signed[0:], 5^QuorumTickResponse)
+	binary.LittleEndian.PutUint16(signed[2:], 150)
+	binary.LittleEndian.PutUint32(signed[4:], 20200000)
+	binary.LittleEndian.PutUint16(signed[8:], 250)
+	copy(signed[10:], []byte{12, 30, 14, 3, 7, 24})
+	binary.LittleEndian.PutUint32(signed[16:], 0x01020304)
+	binary.LittleEndian.PutUint32(signed[20:], 0x05060708)
+	binary.LittleEndian.PutUint32(signed[24:], 0x090a0b0c)
+	binary.LittleEndian.PutUint32(signed[28:], 0x0d0e0f10)
+	for i, digest := range vote.digests() {
+		copy(signed[32+32*i:], digest[:])
+	}
+	expected, err := k12Hash(signed)
+	require.NoError(t, err)
+
+	digest, err := vote.GetDigest()
+	require.NoError(t, err)
+	assert.Equal(t, expected, digest)
+	assert.Equal(t, "5633e8ef4992d7fa3680b5a424460aaf939d730336ad204a354e51df52b1bb12", hex.EncodeToString(digest[:]))
+}
+
+func TestQuorumVotes_UnmarshallFromReader_Signed(t *testing.T) {
+	// QUORUM_TICK_RESPONSE header + vote signed by the test computor + END_RESPONSE header
+	hexStr := "680100030000000005009600403a3401fa000c1e0e03071804030201080706050c0b0a09100f0e0d" +
+		"0100000000000000000000000000000000000000000000000000000000000080" +
+		"0200000000000000000000000000000000000000000000000000000000000081" +
+		"0300000000000000000000000000000000000000000000000000000000000082" +
+		"0400000000000000000000000000000000000000000000000000000000000083" +
+		"0500000000000000000000000000000000000000000000000000000000000084" +
+		"0600000000000000000000000000000000000000000000000000000000000085" +
+		"0700000000000000000000000000000000000000000000000000000000000086" +
+		"0800000000000000000000000000000000000000000000000000000000000087" +
+		"32ea2e2274fa880d9b3983baf428a4426460dd2ef644889d2e53af93f789da9efe3f312f417bd5e160c33b0cb4e34406ffc64e6d02e158c1eadb0b214d640d00" +
+		"0800002300000000"
+
+	votesBin, err := hex.DecodeString(hexStr)
+	require.NoError(t, err)
+
+	var votes QuorumVotes
+	err = votes.UnmarshallFromReader(bytes.NewReader(votesBin))
+	require.NoError(t, err)
+	require.Len(t, votes, 1)
+
+	signature := votes[0].Signature
+	votes[0].Signature = [SignatureSize]byte{}
+	assert.Equal(t, newTestVectorVote(), votes[0])
+	votes[0].Signature = signature
+
+	err = votes[0].Verify(newTestComputors(t))
+	assert.NoError(t, err)
+}
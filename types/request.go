@@ -1,6 +1,11 @@
 package types
 
-import "math/rand"
+import (
+	"encoding/binary"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
 
 // request and response types
 const (
@@ -50,6 +55,17 @@ func (h *RequestResponseHeader) GetSize() uint32 {
 	return result
 }
 
+// GetPayloadSize returns the size of the data following the header. It fails when the announced size is smaller than
+// the header itself.
+func (h *RequestResponseHeader) GetPayloadSize() (uint32, error) {
+	headerSize := uint32(binary.Size(*h))
+	if h.GetSize() < headerSize {
+		return 0, errors.Wrapf(ErrInvalidPacketSize, "size %d is smaller than the %d bytes header", h.GetSize(), headerSize)
+	}
+
+	return h.GetSize() - headerSize, nil
+}
+
 func (h *RequestResponseHeader) SetSize(size uint32) {
 	h.Size[0] = uint8(size)
 	h.Size[1] = uint8(size >> 8)
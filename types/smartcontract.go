@@ -22,12 +22,20 @@ func (scd *SmartContractData) UnmarshallFromReader(r io.Reader) error {
 	}
 
 	if header.Type != ContractFunctionResponse {
-		return errors.Errorf("Invalid header type, expected %d, found %d", ContractFunctionResponse, header.Type)
+		return ErrUnexpectedPacket{Expected: ContractFunctionResponse, Got: header.Type}
 	}
 
-	data := make([]byte, header.GetSize()-uint32(binary.Size(header)))
+	dataSize, err := header.GetPayloadSize()
+	if err != nil {
+		return err
+	}
+
+	if header.GetSize() > MaxPacketSize {
+		return errors.Wrapf(ErrInvalidPacketSize, "size %d exceeds the maximum of %d", header.GetSize(), MaxPacketSize)
+	}
 
-	err = binary.Read(r, binary.LittleEndian, data)
+	data := make([]byte, dataSize)
+	_, err = io.ReadFull(r, data)
 	if err != nil {
 		return errors.Wrap(err, "reading data")
 	}
@@ -53,7 +53,7 @@ func (si *SystemInfo) UnmarshallFromReader(r io.Reader) error {
 	}
 
 	if header.Type != SystemInfoResponse {
-		return errors.Errorf("invalid header type. expected %d, found %d", SystemInfoResponse, header.Type)
+		return ErrUnexpectedPacket{Expected: SystemInfoResponse, Got: header.Type}
 	}
 
 	err = binary.Read(r, binary.LittleEndian, si)
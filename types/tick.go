@@ -1,9 +1,12 @@
 package types
 
 import (
+	"bytes"
 	"encoding/binary"
 	"github.com/pkg/errors"
+	"github.com/qubic/go-schnorrq"
 	"io"
+	"time"
 )
 
 const (
@@ -22,8 +25,8 @@ type TickData struct {
 	Month              uint8
 	Year               uint8
 	Timelock           [32]byte
-	TransactionDigests [NumberOfTransactionsPerTick][32]byte `json:",omitempty"`
-	ContractFees       [1024]int64                           `json:",omitempty"`
+	TransactionDigests [NumberOfTransactionsPerTick]Digest `json:",omitempty"`
+	ContractFees       [1024]int64                         `json:",omitempty"`
 	Signature          [SignatureSize]byte
 }
 
@@ -40,7 +43,7 @@ func (td *TickData) UnmarshallFromReader(r io.Reader) error {
 	}
 
 	if header.Type != BroadcastFutureTickData {
-		return errors.Errorf("Invalid header type, expected %d, found %d", BroadcastFutureTickData, header.Type)
+		return ErrUnexpectedPacket{Expected: BroadcastFutureTickData, Got: header.Type}
 	}
 
 	err = binary.Read(r, binary.LittleEndian, &td.ComputorIndex)
@@ -124,6 +127,60 @@ func (td *TickData) IsEmpty() bool {
 	return *td == TickData{}
 }
 
+// Timestamp returns the UTC time the tick leader set in the tick data. Years are counted from 2000.
+func (td *TickData) Timestamp() time.Time {
+	return time.Date(2000+int(td.Year), time.Month(td.Month), int(td.Day), int(td.Hour), int(td.Minute), int(td.Second),
+		int(td.Millisecond)*int(time.Millisecond), time.UTC)
+}
+
+// GetDigest returns the K12 digest signed by the tick leader. As the node does, the computor index is xor-ed with the
+// packet type before hashing.
+func (td *TickData) GetDigest() ([32]byte, error) {
+	data := *td
+	data.ComputorIndex ^= BroadcastFutureTickData
+
+	var buff bytes.Buffer
+	err := binary.Write(&buff, binary.LittleEndian, data)
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "serializing tick data")
+	}
+
+	serialized := buff.Bytes()
+	digest, err := k12Hash(serialized[:len(serialized)-SignatureSize])
+	if err != nil {
+		return [32]byte{}, errors.Wrap(err, "hashing tick data")
+	}
+
+	return digest, nil
+}
+
+// Verify checks that the tick data is signed by the computor at ComputorIndex in the given computor list.
+func (td *TickData) Verify(computors Computors) error {
+	if td.IsEmpty() {
+		return ErrEmptyTick
+	}
+
+	if td.Epoch != computors.Epoch {
+		return errors.Errorf("tick data epoch %d does not match computors epoch %d", td.Epoch, computors.Epoch)
+	}
+
+	if td.ComputorIndex >= NumberOfComputors {
+		return errors.Errorf("invalid computor index %d", td.ComputorIndex)
+	}
+
+	digest, err := td.GetDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting tick data digest")
+	}
+
+	err = schnorrq.Verify(computors.PubKeys[td.ComputorIndex], digest, td.Signature)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "verifying signature of computor %d: %s", td.ComputorIndex, err)
+	}
+
+	return nil
+}
+
 type TickInfo struct {
 	TickDuration            uint16
 	Epoch                   uint16
@@ -143,16 +200,15 @@ func (ti *TickInfo) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type == 0 {
-			ignoredBytes := make([]byte, header.GetSize()-uint32(binary.Size(header)))
-			_, err := r.Read(ignoredBytes)
+			err := skipPayload(r, header)
 			if err != nil {
-				return errors.Wrap(err, "reading ignored bytes")
+				return errors.Wrap(err, "skipping ignored packet")
 			}
 			continue
 		}
 
 		if header.Type != CurrentTickInfoResponse {
-			return errors.Errorf("Invalid header type, expected %d, found %d", CurrentTickInfoResponse, header.Type)
+			return ErrUnexpectedPacket{Expected: CurrentTickInfoResponse, Got: header.Type}
 		}
 
 		err = binary.Read(r, binary.LittleEndian, ti)
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestTickData_Verify(t *testing.T) {
+	computors := newTestComputors(t)
+
+	tickData := TickData{
+		ComputorIndex: 5,
+		Epoch:         150,
+		Tick:          20200000,
+		Second:        12,
+		Year:          24,
+	}
+	tickData.TransactionDigests[0] = [32]byte{1, 2, 3}
+	digest, err := tickData.GetDigest()
+	require.NoError(t, err)
+	tickData.Signature = signDigest(t, testComputorSeed, digest)
+
+	err = tickData.Verify(computors)
+	assert.NoError(t, err)
+
+	tampered := tickData
+	tampered.TransactionDigests[1] = [32]byte{4}
+	err = tampered.Verify(computors)
+	assert.Error(t, err)
+
+	var empty TickData
+	err = empty.Verify(computors)
+	assert.Error(t, err)
+}
+
+func TestTickData_Timestamp(t *testing.T) {
+	tickData := TickData{Millisecond: 250, Second: 12, Minute: 30, Hour: 14, Day: 3, Month: 7, Year: 24}
+
+	assert.Equal(t, time.Date(2024, time.July, 3, 14, 30, 12, 250*int(time.Millisecond), time.UTC), tickData.Timestamp())
+}
+
+func TestTickData_DigestLayout(t *testing.T) {
+	tickData := TickData{
+		ComputorIndex: 5,
+		Epoch:         150,
+		Tick:          20200000,
+		Millisecond:   250,
+		Second:        12,
+		Minute:        30,
+		Hour:          14,
+		Day:           3,
+		Month:         7,
+		Year:          24,
+		Timelock:      [32]byte{1, 2, 3},
+	}
+	tickData.TransactionDigests[0] = [32]byte{4, 5, 6}
+	tickData.TransactionDigests[NumberOfTransactionsPerTick-1] = [32]byte{7, 8, 9}
+	tickData.ContractFees[0] = 1000
+	tickData.ContractFees[len(tickData.ContractFees)-1] = -1
+
+	// the signed bytes of a tick data in the core layout, the computor index xor-ed with BROADCAST_FUTURE_TICK_DATA
+	signed := make([]byte, 48+32*NumberOfTransactionsPerTick+8*len(tickData.ContractFees))
+	binary.LittleEndian.PutUint16(signed[0:], 5^BroadcastFutureTickData)
+	binary.LittleEndian.PutUint16(signed[2:], 150)
+	binary.LittleEndian.PutUint32(signed[4:], 20200000)
+	binary.LittleEndian.PutUint16(signed[8:], 250)
+	copy(signed[10:], []byte{12, 30, 14, 3, 7, 24})
+	copy(signed[16:], []byte{1, 2, 3})
+	copy(signed[48:], []byte{4, 5, 6})
+	copy(signed[48+32*(NumberOfTransactionsPerTick-1):], []byte{7, 8, 9})
+	fees := 48 + 32*NumberOfTransactionsPerTick
+	binary.LittleEndian.PutUint64(signed[fees:], 1000)
+	binary.LittleEndian.PutUint64(signed[len(signed)-8:], 0xffffffffffffffff)
+	expected, err := k12Hash(signed)
+	require.NoError(t, err)
+
+	digest, err := tickData.GetDigest()
+	require.NoError(t, err)
+	assert.Equal(t, expected, digest)
+	assert.Equal(t, "cb70f43fb7b1cabb8c5250d38aac88a89d7b21a91765a70dd485c3672e5f20e1", hex.EncodeToString(digest[:]))
+
+	// signature of the test computor over the digest
+	signature, err := hex.DecodeString("d4c89a6250967c1bf9734f9ee29ada0414252d14978f335074f68185b85e4abccddfa1a33443108a76d6fe02d4843eecd129acaffe3fa50d929ba3223bd90f00")
+	require.NoError(t, err)
+	copy(tickData.Signature[:], signature)
+	err = tickData.Verify(newTestComputors(t))
+	assert.NoError(t, err)
+}
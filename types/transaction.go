@@ -10,6 +10,9 @@ import (
 	"io"
 )
 
+// MaxInputSize is the largest input a node accepts in a transaction.
+const MaxInputSize = 1024
+
 type Transaction struct {
 	SourcePublicKey      [32]byte
 	DestinationPublicKey [32]byte
@@ -125,33 +128,28 @@ func (tx *Transaction) UnmarshallBinary(r io.Reader) error {
 	return nil
 }
 
-func (tx *Transaction) Digest() ([32]byte, error) {
+func (tx *Transaction) Digest() (Digest, error) {
 	serialized, err := tx.MarshallBinary()
 	if err != nil {
-		return [32]byte{}, errors.Wrap(err, "marshalling tx data")
+		return Digest{}, errors.Wrap(err, "marshalling tx data")
 	}
 
 	digest, err := k12Hash(serialized)
 	if err != nil {
-		return [32]byte{}, errors.Wrap(err, "hashing tx data")
+		return Digest{}, errors.Wrap(err, "hashing tx data")
 	}
 
 	return digest, nil
 }
 
+// ID returns the digest in lower case identity form, ParseDigest turns it back into the digest.
 func (tx *Transaction) ID() (string, error) {
 	digest, err := tx.Digest()
 	if err != nil {
 		return "", errors.Wrap(err, "getting digest")
 	}
 
-	var id Identity
-	id, err = id.FromPubKey(digest, true)
-	if err != nil {
-		return "", errors.Wrap(err, "getting id from pubkey")
-	}
-
-	return id.String(), nil
+	return digest.String(), nil
 }
 
 func (tx *Transaction) EncodeToBase64() (string, error) {
@@ -198,7 +196,11 @@ func (txs *Transactions) UnmarshallFromReader(r io.Reader) error {
 		}
 
 		if header.Type != BroadcastTransaction {
-			return errors.Errorf("Invalid header type, expected %d, found %d", BroadcastTransaction, header.Type)
+			return ErrUnexpectedPacket{Expected: BroadcastTransaction, Got: header.Type}
+		}
+
+		if len(*txs) >= NumberOfTransactionsPerTick {
+			return errTooManyPackets(NumberOfTransactionsPerTick)
 		}
 
 		var tx Transaction
@@ -219,7 +221,7 @@ type TransactionStatus struct {
 	Tick               uint32
 	TxCount            uint32
 	MoneyFlew          [(NumberOfTransactionsPerTick + 7) / 8]byte
-	TransactionDigests [][32]byte
+	TransactionDigests []Digest
 }
 
 func (ts *TransactionStatus) UnmarshallFromReader(r io.Reader) error {
@@ -231,7 +233,7 @@ func (ts *TransactionStatus) UnmarshallFromReader(r io.Reader) error {
 	}
 
 	if header.Type != TxStatusResponse {
-		return errors.Errorf("Invalid header type, expected %d, found %d", TxStatusResponse, header.Type)
+		return ErrUnexpectedPacket{Expected: TxStatusResponse, Got: header.Type}
 	}
 
 	err = binary.Read(r, binary.LittleEndian, &ts.CurrentTickOfNode)
@@ -254,7 +256,11 @@ func (ts *TransactionStatus) UnmarshallFromReader(r io.Reader) error {
 		return errors.Wrap(err, "reading reading money flew")
 	}
 
-	ts.TransactionDigests = make([][32]byte, ts.TxCount)
+	if ts.TxCount > NumberOfTransactionsPerTick {
+		return errors.Errorf("tx count %d exceeds the maximum of %d", ts.TxCount, NumberOfTransactionsPerTick)
+	}
+
+	ts.TransactionDigests = make([]Digest, ts.TxCount)
 	err = binary.Read(r, binary.LittleEndian, &ts.TransactionDigests)
 	if err != nil {
 		return errors.Wrap(err, "reading tx digests")
@@ -73,6 +73,61 @@ func (atp *AssetTransferPayload) MarshallBinary() ([]byte, error) {
 	return buff.Bytes(), nil
 }
 
+func (atp *AssetTransferPayload) UnmarshallBinary(b []byte) error {
+	reader := bytes.NewReader(b)
+
+	err := binary.Read(reader, binary.LittleEndian, &atp.issuer)
+	if err != nil {
+		return errors.Wrap(err, "reading issuer public key from reader")
+	}
+
+	err = binary.Read(reader, binary.LittleEndian, &atp.newOwnerAndPossessor)
+	if err != nil {
+		return errors.Wrap(err, "reading new owner and possessor public key from reader")
+	}
+
+	err = binary.Read(reader, binary.LittleEndian, &atp.assetName)
+	if err != nil {
+		return errors.Wrap(err, "reading asset name from reader")
+	}
+
+	err = binary.Read(reader, binary.LittleEndian, &atp.numberOfUnits)
+	if err != nil {
+		return errors.Wrap(err, "reading number of units from reader")
+	}
+
+	return nil
+}
+
+func (atp *AssetTransferPayload) GetIssuer() (Identity, error) {
+	var issuer Identity
+	issuer, err := issuer.FromPubKey(atp.issuer, false)
+	if err != nil {
+		return "", errors.Wrap(err, "getting issuer identity from public key")
+	}
+
+	return issuer, nil
+}
+
+func (atp *AssetTransferPayload) GetNewOwnerAndPossessor() (Identity, error) {
+	var newOwner Identity
+	newOwner, err := newOwner.FromPubKey(atp.newOwnerAndPossessor, false)
+	if err != nil {
+		return "", errors.Wrap(err, "getting new owner and possessor identity from public key")
+	}
+
+	return newOwner, nil
+}
+
+// GetAssetName returns the asset name without its zero padding.
+func (atp *AssetTransferPayload) GetAssetName() string {
+	return string(bytes.TrimRight(atp.assetName[:], "\x00"))
+}
+
+func (atp *AssetTransferPayload) GetNumberOfUnits() int64 {
+	return atp.numberOfUnits
+}
+
 func NewAssetTransferTransaction(sourceID string, targetTick uint32, transferFee int64, payload AssetTransferPayload) (Transaction, error) {
 
 	sourceIdentity := Identity(sourceID)
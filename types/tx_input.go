@@ -0,0 +1,78 @@
+package types
+
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// InputDecoder decodes the input of a transaction into its typed payload.
+type InputDecoder func(input []byte) (interface{}, error)
+
+type inputDecoderKey struct {
+	destination [32]byte
+	inputType   uint16
+}
+
+var (
+	inputDecodersMu sync.RWMutex
+	inputDecoders   = make(map[inputDecoderKey]InputDecoder)
+)
+
+// RegisterInputDecoder sets the decoder used for the inputs of the given type sent to the destination contract,
+// replacing the existing one if any.
+func RegisterInputDecoder(destination [32]byte, inputType uint16, decoder InputDecoder) {
+	inputDecodersMu.Lock()
+	defer inputDecodersMu.Unlock()
+	inputDecoders[inputDecoderKey{destination: destination, inputType: inputType}] = decoder
+}
+
+// DecodeInput decodes the input with the decoder registered for the destination and input type of the transaction.
+// It returns a SendManyTransferPayload for QUTIL send many, an AssetTransferPayload for QX transfers, a
+// QxIssueAssetPayload for QX issuances and a QxOrderPayload for QX order changes. Inputs without a decoder, such as
+// the empty input of a simple transfer, decode to nil.
+func (tx *Transaction) DecodeInput() (interface{}, error) {
+	inputDecodersMu.RLock()
+	decoder, ok := inputDecoders[inputDecoderKey{destination: tx.DestinationPublicKey, inputType: tx.InputType}]
+	inputDecodersMu.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	if int(tx.InputSize) != len(tx.Input) {
+		return nil, errors.Errorf("input size %d does not match input length %d", tx.InputSize, len(tx.Input))
+	}
+
+	payload, err := decoder(tx.Input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "decoding input type %d", tx.InputType)
+	}
+
+	return payload, nil
+}
+
+func init() {
+	RegisterInputDecoder(ContractPublicKey(QutilContractIndex), QutilSendManyInputType, func(input []byte) (interface{}, error) {
+		var payload SendManyTransferPayload
+		err := payload.UnmarshallBinary(input)
+		return payload, err
+	})
+	RegisterInputDecoder(ContractPublicKey(QxContractIndex), QxTransferInputType, func(input []byte) (interface{}, error) {
+		var payload AssetTransferPayload
+		err := payload.UnmarshallBinary(input)
+		return payload, err
+	})
+	RegisterInputDecoder(ContractPublicKey(QxContractIndex), QxIssueAssetInputType, func(input []byte) (interface{}, error) {
+		var payload QxIssueAssetPayload
+		err := payload.UnmarshallBinary(input)
+		return payload, err
+	})
+	for _, inputType := range []uint16{QxAddToAskOrderInputType, QxAddToBidOrderInputType, QxRemoveFromAskOrderInputType, QxRemoveFromBidOrderInputType} {
+		RegisterInputDecoder(ContractPublicKey(QxContractIndex), inputType, func(input []byte) (interface{}, error) {
+			var payload QxOrderPayload
+			err := payload.UnmarshallBinary(input)
+			return payload, err
+		})
+	}
+}
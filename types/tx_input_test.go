@@ -0,0 +1,74 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_DecodeInput(t *testing.T) {
+	var sendMany SendManyTransferPayload
+	require.NoError(t, sendMany.AddTransfers([]SendManyTransfer{
+		{AddressID: validationTestDest, Amount: 10},
+		{AddressID: validationTestIdentity, Amount: 20},
+	}))
+	tx, err := NewSendManyTransferTransaction(validationTestIdentity, 1000, sendMany)
+	require.NoError(t, err)
+
+	decoded, err := tx.DecodeInput()
+	require.NoError(t, err)
+	payload, ok := decoded.(SendManyTransferPayload)
+	require.True(t, ok)
+	transfers, err := payload.GetTransfers()
+	require.NoError(t, err)
+	assert.Equal(t, []SendManyTransfer{
+		{AddressID: validationTestDest, Amount: 10},
+		{AddressID: validationTestIdentity, Amount: 20},
+	}, transfers)
+	assert.Equal(t, int64(30+QutilSendManyFee), payload.GetTotalAmount())
+	require.NoError(t, payload.AddTransfer(SendManyTransfer{AddressID: validationTestDest, Amount: 5}))
+	transfers, err = payload.GetTransfers()
+	require.NoError(t, err)
+	assert.Len(t, transfers, 3)
+
+	assetTransfer, err := NewAssetTransferPayload("CFB", validationTestDest, validationTestIdentity, 7)
+	require.NoError(t, err)
+	tx, err = NewAssetTransferTransaction(validationTestIdentity, 1000, 100, assetTransfer)
+	require.NoError(t, err)
+
+	decoded, err = tx.DecodeInput()
+	require.NoError(t, err)
+	asset, ok := decoded.(AssetTransferPayload)
+	require.True(t, ok)
+	assert.Equal(t, "CFB", asset.GetAssetName())
+	assert.Equal(t, int64(7), asset.GetNumberOfUnits())
+	issuer, err := asset.GetIssuer()
+	require.NoError(t, err)
+	assert.Equal(t, Identity(validationTestDest), issuer)
+	newOwner, err := asset.GetNewOwnerAndPossessor()
+	require.NoError(t, err)
+	assert.Equal(t, Identity(validationTestIdentity), newOwner)
+
+	tx, err = NewSimpleTransferTransaction(validationTestIdentity, validationTestDest, 1, 1000)
+	require.NoError(t, err)
+	decoded, err = tx.DecodeInput()
+	require.NoError(t, err)
+	assert.Nil(t, decoded)
+}
+
+func TestRegisterInputDecoder(t *testing.T) {
+	destination := [32]byte{1}
+	RegisterInputDecoder(destination, 9, func(input []byte) (interface{}, error) {
+		return len(input), nil
+	})
+
+	tx := Transaction{DestinationPublicKey: destination, InputType: 9, InputSize: 3, Input: []byte{1, 2, 3}}
+	decoded, err := tx.DecodeInput()
+	require.NoError(t, err)
+	assert.Equal(t, 3, decoded)
+
+	tx.InputSize = 4
+	_, err = tx.DecodeInput()
+	require.Error(t, err)
+}
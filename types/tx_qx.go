@@ -0,0 +1,218 @@
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"github.com/pkg/errors"
+)
+
+const (
+	QxIssueAssetInputType         = 1
+	QxAddToAskOrderInputType      = 5
+	QxAddToBidOrderInputType      = 6
+	QxRemoveFromAskOrderInputType = 7
+	QxRemoveFromBidOrderInputType = 8
+)
+
+// QxIssueAssetInputSize includes the 7 bytes padding the contract adds after the number of decimal places.
+const QxIssueAssetInputSize = 8 + 8 + 8 + 1 + 7
+const QxOrderInputSize = 32 + 8 + 8 + 8
+
+type QxIssueAssetPayload struct {
+	assetName             [8]byte
+	numberOfShares        int64
+	unitOfMeasurement     [8]byte
+	numberOfDecimalPlaces int8
+}
+
+// NewQxIssueAssetPayload creates the payload issuing numberOfShares of a new asset owned by the source of the
+// transaction. The unit of measurement holds the exponents of the 7 SI base units, as in IssuedAssetData.
+func NewQxIssueAssetPayload(assetName string, numberOfShares int64, unitOfMeasurement [7]int8, numberOfDecimalPlaces int8) (QxIssueAssetPayload, error) {
+	if len(assetName) == 0 || len(assetName) > 7 {
+		return QxIssueAssetPayload{}, errors.Errorf("asset name '%s' must be 1 to 7 characters long", assetName)
+	}
+
+	if numberOfShares <= 0 {
+		return QxIssueAssetPayload{}, errors.Errorf("number of shares must be positive, got %d", numberOfShares)
+	}
+
+	payload := QxIssueAssetPayload{numberOfShares: numberOfShares, numberOfDecimalPlaces: numberOfDecimalPlaces}
+	copy(payload.assetName[:], assetName)
+	for i, exponent := range unitOfMeasurement {
+		payload.unitOfMeasurement[i] = byte(exponent)
+	}
+
+	return payload, nil
+}
+
+func (p *QxIssueAssetPayload) MarshallBinary() ([]byte, error) {
+	var buff bytes.Buffer
+
+	err := binary.Write(&buff, binary.LittleEndian, p.assetName)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing asset name to buffer")
+	}
+
+	err = binary.Write(&buff, binary.LittleEndian, p.numberOfShares)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing number of shares to buffer")
+	}
+
+	err = binary.Write(&buff, binary.LittleEndian, p.unitOfMeasurement)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing unit of measurement to buffer")
+	}
+
+	err = binary.Write(&buff, binary.LittleEndian, p.numberOfDecimalPlaces)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing number of decimal places to buffer")
+	}
+
+	buff.Write(make([]byte, QxIssueAssetInputSize-buff.Len()))
+
+	return buff.Bytes(), nil
+}
+
+func (p *QxIssueAssetPayload) UnmarshallBinary(b []byte) error {
+	if len(b) != QxIssueAssetInputSize {
+		return errors.Errorf("issue asset input must be %d bytes, got %d", QxIssueAssetInputSize, len(b))
+	}
+
+	copy(p.assetName[:], b[0:8])
+	p.numberOfShares = int64(binary.LittleEndian.Uint64(b[8:16]))
+	copy(p.unitOfMeasurement[:], b[16:24])
+	p.numberOfDecimalPlaces = int8(b[24])
+
+	return nil
+}
+
+func (p *QxIssueAssetPayload) GetAssetName() string {
+	return string(bytes.TrimRight(p.assetName[:], "\x00"))
+}
+
+func (p *QxIssueAssetPayload) GetNumberOfShares() int64 {
+	return p.numberOfShares
+}
+
+func (p *QxIssueAssetPayload) GetUnitOfMeasurement() [7]int8 {
+	var unit [7]int8
+	for i := range unit {
+		unit[i] = int8(p.unitOfMeasurement[i])
+	}
+
+	return unit
+}
+
+func (p *QxIssueAssetPayload) GetNumberOfDecimalPlaces() int8 {
+	return p.numberOfDecimalPlaces
+}
+
+// NewQxIssueAssetTransaction creates the transaction issuing an asset. The issuance fee is returned by the QX Fees
+// function.
+func NewQxIssueAssetTransaction(sourceID string, targetTick uint32, issuanceFee int64, payload QxIssueAssetPayload) (Transaction, error) {
+	input, err := payload.MarshallBinary()
+	if err != nil {
+		return Transaction{}, errors.Wrap(err, "marshalling transaction payload to binary format")
+	}
+
+	return NewContractProcedureTransaction(sourceID, QxContractIndex, QxIssueAssetInputType, input, issuanceFee, targetTick)
+}
+
+// QxOrderPayload is the input of the procedures adding and removing ask and bid orders.
+type QxOrderPayload struct {
+	issuer         [32]byte
+	assetName      [8]byte
+	price          int64
+	numberOfShares int64
+}
+
+func NewQxOrderPayload(assetName, issuer string, price, numberOfShares int64) (QxOrderPayload, error) {
+	issuerIdentity := Identity(issuer)
+	issuerPubKey, err := issuerIdentity.ToPubKey(false)
+	if err != nil {
+		return QxOrderPayload{}, errors.Wrap(err, "failed to obtain issuer public key")
+	}
+
+	if len(assetName) > 7 {
+		return QxOrderPayload{}, errors.Errorf("asset name '%s' is longer than 7", assetName)
+	}
+
+	if price <= 0 || numberOfShares <= 0 {
+		return QxOrderPayload{}, errors.Errorf("price and number of shares must be positive, got %d and %d", price, numberOfShares)
+	}
+
+	payload := QxOrderPayload{issuer: issuerPubKey, price: price, numberOfShares: numberOfShares}
+	copy(payload.assetName[:], assetName)
+
+	return payload, nil
+}
+
+func (p *QxOrderPayload) MarshallBinary() ([]byte, error) {
+	var buff bytes.Buffer
+
+	err := binary.Write(&buff, binary.LittleEndian, p)
+	if err != nil {
+		return nil, errors.Wrap(err, "writing order to buffer")
+	}
+
+	return buff.Bytes(), nil
+}
+
+func (p *QxOrderPayload) UnmarshallBinary(b []byte) error {
+	if len(b) != QxOrderInputSize {
+		return errors.Errorf("order input must be %d bytes, got %d", QxOrderInputSize, len(b))
+	}
+
+	copy(p.issuer[:], b[0:32])
+	copy(p.assetName[:], b[32:40])
+	p.price = int64(binary.LittleEndian.Uint64(b[40:48]))
+	p.numberOfShares = int64(binary.LittleEndian.Uint64(b[48:56]))
+
+	return nil
+}
+
+func (p *QxOrderPayload) GetIssuer() (Identity, error) {
+	var issuer Identity
+	issuer, err := issuer.FromPubKey(p.issuer, false)
+	if err != nil {
+		return "", errors.Wrap(err, "getting issuer identity from public key")
+	}
+
+	return issuer, nil
+}
+
+func (p *QxOrderPayload) GetAssetName() string {
+	return string(bytes.TrimRight(p.assetName[:], "\x00"))
+}
+
+func (p *QxOrderPayload) GetPrice() int64 {
+	return p.price
+}
+
+func (p *QxOrderPayload) GetNumberOfShares() int64 {
+	return p.numberOfShares
+}
+
+// NewQxOrderTransaction creates the transaction adding or removing an order, inputType is one of
+// QxAddToAskOrderInputType, QxAddToBidOrderInputType, QxRemoveFromAskOrderInputType and QxRemoveFromBidOrderInputType.
+// Adding a bid order locks price * number of shares, which is sent as the transaction amount.
+func NewQxOrderTransaction(sourceID string, targetTick uint32, inputType uint16, payload QxOrderPayload) (Transaction, error) {
+	var amount int64
+	switch inputType {
+	case QxAddToBidOrderInputType:
+		amount = payload.price * payload.numberOfShares
+		if amount/payload.numberOfShares != payload.price {
+			return Transaction{}, errors.Errorf("bid of %d shares at %d overflows", payload.numberOfShares, payload.price)
+		}
+	case QxAddToAskOrderInputType, QxRemoveFromAskOrderInputType, QxRemoveFromBidOrderInputType:
+	default:
+		return Transaction{}, errors.Errorf("input type %d is not a qx order procedure", inputType)
+	}
+
+	input, err := payload.MarshallBinary()
+	if err != nil {
+		return Transaction{}, errors.Wrap(err, "marshalling transaction payload to binary format")
+	}
+
+	return NewContractProcedureTransaction(sourceID, QxContractIndex, inputType, input, amount, targetTick)
+}
@@ -0,0 +1,54 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewQxIssueAssetTransaction(t *testing.T) {
+	payload, err := NewQxIssueAssetPayload("MYASSET", 1000, [7]int8{0, 0, 0, 0, 0, 0, 1}, 2)
+	require.NoError(t, err)
+
+	tx, err := NewQxIssueAssetTransaction(validationTestIdentity, 1000, 1000000000, payload)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000000000), tx.Amount)
+	assert.Equal(t, uint16(QxIssueAssetInputSize), tx.InputSize)
+
+	decoded, err := tx.DecodeInput()
+	require.NoError(t, err)
+	got := decoded.(QxIssueAssetPayload)
+	assert.Equal(t, "MYASSET", got.GetAssetName())
+	assert.Equal(t, int64(1000), got.GetNumberOfShares())
+	assert.Equal(t, [7]int8{0, 0, 0, 0, 0, 0, 1}, got.GetUnitOfMeasurement())
+	assert.Equal(t, int8(2), got.GetNumberOfDecimalPlaces())
+
+	_, err = NewQxIssueAssetPayload("TOOLONGNAME", 1000, [7]int8{}, 0)
+	require.Error(t, err)
+}
+
+func TestNewQxOrderTransaction(t *testing.T) {
+	payload, err := NewQxOrderPayload("CFB", validationTestDest, 3, 4)
+	require.NoError(t, err)
+
+	bid, err := NewQxOrderTransaction(validationTestIdentity, 1000, QxAddToBidOrderInputType, payload)
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), bid.Amount)
+	assert.Equal(t, uint16(QxOrderInputSize), bid.InputSize)
+
+	decoded, err := bid.DecodeInput()
+	require.NoError(t, err)
+	got := decoded.(QxOrderPayload)
+	assert.Equal(t, payload, got)
+	issuer, err := got.GetIssuer()
+	require.NoError(t, err)
+	assert.Equal(t, Identity(validationTestDest), issuer)
+
+	ask, err := NewQxOrderTransaction(validationTestIdentity, 1000, QxAddToAskOrderInputType, payload)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), ask.Amount)
+
+	_, err = NewQxOrderTransaction(validationTestIdentity, 1000, QxTransferInputType, payload)
+	require.Error(t, err)
+}
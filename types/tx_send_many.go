@@ -108,12 +108,18 @@ func (smp *SendManyTransferPayload) UnmarshallBinary(b []byte) error {
 	}
 
 	totalAmount := int64(0)
+	filledTransfers := int8(0)
 
-	for _, amount := range smp.amounts {
+	for index, amount := range smp.amounts {
 		totalAmount += amount
+		if smp.addresses[index] != [32]byte{} {
+			filledTransfers = int8(index + 1)
+		}
 	}
 
 	smp.totalAmount = totalAmount
+	// AddTransfer appends after the last filled slot
+	smp.filledTransfers = filledTransfers
 
 	return nil
 }
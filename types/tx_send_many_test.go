@@ -15,10 +15,10 @@ func TestSendManyTransaction(t *testing.T) {
 			senderSeed:     "yfcqxawkwvhnwwxnhxqbzufpnbxxvkpuueermpcxoiugqokwbmurqjq",
 			senderIdentity: "LZTPJBQKOYLBFEWWFVEFDFOOFEWCUSSNNKLOXGDQJGBTYUMJVAOSYHIGYDOM",
 			transfers: map[string]int64{
-				"AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA": 10,
-				"BBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBBB": 20,
-				"CCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCCC": 30,
-				"DDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDDD": 40,
+				"QJRRSSKMJRDKUDTYVNYGAMQPULKAMILQQYOWBEXUDEUWQUMNGDHQYLOAJMEB": 10,
+				"ZFEEMHFUDDGUJBFXDVHXOHKDSELCAWDCUTASNOAMQDTZWILDTCDCSNQGHEGN": 20,
+				"COVLRIWUCHTKZFGXEFYFSVNWDXECAYJXXSLSKDETUCCDMTRTAWCLSFOCJJSA": 30,
+				"CSPGQLVUJIUCFESXLVBHVYSGDRLDJNVUGWMKQLPYNFKRPQAAFOKILXBFIIUJ": 40,
 			},
 		},
 	}
@@ -0,0 +1,182 @@
+package types
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/qubic/go-schnorrq"
+)
+
+// ValidationOptions enable the checks that need the state of the network. Zero values skip them.
+type ValidationOptions struct {
+	// CurrentTick is the tick of the node the transaction is sent to. When set, the target tick must be after it.
+	CurrentTick uint32
+	// MaxTickOffset is how far ahead of CurrentTick the target tick may be. Zero means no limit.
+	MaxTickOffset uint32
+	// Source is the source entity, as returned by GetIdentity. When set, its balance must cover the amount.
+	Source *AddressInfo
+	// QxTransferFee is the current QX asset transfer fee. When set, QX transfers must pay at least that much.
+	QxTransferFee int64
+}
+
+// ValidateTransaction runs the checks a node or a contract would run on the transaction and returns the first one
+// that fails, as an ErrInvalidTransaction. Transactions that pass can still fail on chain, e.g. if the balance changes
+// before the target tick.
+func ValidateTransaction(tx Transaction, opts ValidationOptions) error {
+	err := validateTransaction(tx, opts)
+	if err != nil {
+		return ErrInvalidTransaction{Err: err}
+	}
+
+	return nil
+}
+
+func validateTransaction(tx Transaction, opts ValidationOptions) error {
+	if int(tx.InputSize) != len(tx.Input) {
+		return errors.Errorf("input size %d does not match input length %d", tx.InputSize, len(tx.Input))
+	}
+
+	if tx.Amount < 0 {
+		return errors.Errorf("negative amount %d", tx.Amount)
+	}
+
+	err := verifyTransactionSignature(tx)
+	if err != nil {
+		return err
+	}
+
+	if opts.CurrentTick != 0 {
+		if tx.Tick <= opts.CurrentTick {
+			return errors.Errorf("target tick %d is not after current tick %d", tx.Tick, opts.CurrentTick)
+		}
+		if opts.MaxTickOffset != 0 && tx.Tick > opts.CurrentTick+opts.MaxTickOffset {
+			return errors.Errorf("target tick %d is more than %d ticks after current tick %d", tx.Tick, opts.MaxTickOffset, opts.CurrentTick)
+		}
+	}
+
+	if opts.Source != nil {
+		if opts.Source.AddressData.PublicKey != tx.SourcePublicKey {
+			return errors.New("source entity does not match the transaction source")
+		}
+
+		balance := opts.Source.AddressData.IncomingAmount - opts.Source.AddressData.OutgoingAmount
+		if balance < tx.Amount {
+			return errors.Errorf("balance %d does not cover amount %d", balance, tx.Amount)
+		}
+	}
+
+	return validateContractInput(tx, opts)
+}
+
+func verifyTransactionSignature(tx Transaction) error {
+	if tx.Signature == [64]byte{} {
+		return ErrTxNotSigned
+	}
+
+	digest, err := tx.GetUnsignedDigest()
+	if err != nil {
+		return errors.Wrap(err, "getting unsigned digest")
+	}
+
+	err = schnorrq.Verify(tx.SourcePublicKey, digest, tx.Signature)
+	if err != nil {
+		return errors.Wrapf(ErrInvalidSignature, "verifying signature: %s", err)
+	}
+
+	return nil
+}
+
+func validateContractInput(tx Transaction, opts ValidationOptions) error {
+	switch {
+	case tx.DestinationPublicKey == ContractPublicKey(QutilContractIndex) && tx.InputType == QutilSendManyInputType:
+		return validateSendMany(tx)
+	case tx.DestinationPublicKey == ContractPublicKey(QxContractIndex) && tx.InputType == QxTransferInputType:
+		return validateQxTransfer(tx, opts)
+	}
+
+	return nil
+}
+
+func validateSendMany(tx Transaction) error {
+	if tx.InputSize != QutilSendManyInputSize {
+		return errors.Errorf("send many input size must be %d, got %d", QutilSendManyInputSize, tx.InputSize)
+	}
+
+	var payload SendManyTransferPayload
+	err := payload.UnmarshallBinary(tx.Input)
+	if err != nil {
+		return errors.Wrap(err, "unmarshalling send many payload")
+	}
+
+	var total int64
+	for i, amount := range payload.amounts {
+		if amount < 0 {
+			return errors.Errorf("send many transfer %d has negative amount %d", i, amount)
+		}
+		if amount > 0 && payload.addresses[i] == [32]byte{} {
+			return errors.Errorf("send many transfer %d has an amount but no destination", i)
+		}
+		total += amount
+	}
+
+	if tx.Amount < total+QutilSendManyFee {
+		return errors.Errorf("amount %d does not cover send many transfers %d plus fee %d", tx.Amount, total, QutilSendManyFee)
+	}
+
+	return nil
+}
+
+func validateQxTransfer(tx Transaction, opts ValidationOptions) error {
+	if tx.InputSize != QxTransferInputSize {
+		return errors.Errorf("qx transfer input size must be %d, got %d", QxTransferInputSize, tx.InputSize)
+	}
+
+	var payload AssetTransferPayload
+	err := payload.UnmarshallBinary(tx.Input)
+	if err != nil {
+		return errors.Wrap(err, "unmarshalling qx transfer payload")
+	}
+
+	err = validateAssetName(payload.assetName)
+	if err != nil {
+		return err
+	}
+
+	if payload.numberOfUnits <= 0 {
+		return errors.Errorf("qx transfer of %d units", payload.numberOfUnits)
+	}
+
+	if payload.newOwnerAndPossessor == [32]byte{} {
+		return errors.New("qx transfer has no new owner")
+	}
+
+	if opts.QxTransferFee != 0 && tx.Amount < opts.QxTransferFee {
+		return errors.Errorf("amount %d does not cover qx transfer fee %d", tx.Amount, opts.QxTransferFee)
+	}
+
+	return nil
+}
+
+// validateAssetName checks the 1 to 7 characters of a zero padded asset name: an upper case letter followed by upper
+// case letters or digits.
+func validateAssetName(name [8]byte) error {
+	length := bytes.IndexByte(name[:], 0)
+	if length == -1 || length > 7 {
+		return errors.New("asset name is longer than 7 characters")
+	}
+	if length == 0 {
+		return errors.New("asset name is empty")
+	}
+	if bytes.Count(name[length:], []byte{0}) != len(name)-length {
+		return errors.Errorf("asset name %q is not zero padded", name[:])
+	}
+
+	for i, c := range name[:length] {
+		isLetter := c >= 'A' && c <= 'Z'
+		isDigit := c >= '0' && c <= '9'
+		if !isLetter && (i == 0 || !isDigit) {
+			return errors.Errorf("invalid character %q in asset name %q", c, name[:length])
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	validationTestSeed     = "yfcqxawkwvhnwwxnhxqbzufpnbxxvkpuueermpcxoiugqokwbmurqjq"
+	validationTestIdentity = "LZTPJBQKOYLBFEWWFVEFDFOOFEWCUSSNNKLOXGDQJGBTYUMJVAOSYHIGYDOM"
+	validationTestDest     = "UIJLDDELETUYEHFKZPQGVOOOTLHCNQWAZAXHLSXWMEDLRQEWKNSJVZIGFPBD"
+)
+
+func signValidationTestTx(t *testing.T, tx Transaction) Transaction {
+	signer, err := NewSigner(validationTestSeed)
+	require.NoError(t, err)
+
+	tx, err = signer.SignTx(tx)
+	require.NoError(t, err)
+
+	return tx
+}
+
+func TestValidateTransaction(t *testing.T) {
+	tx, err := NewSimpleTransferTransaction(validationTestIdentity, validationTestDest, 100, 1000)
+	require.NoError(t, err)
+	tx = signValidationTestTx(t, tx)
+
+	source := &AddressInfo{AddressData: AddressData{PublicKey: tx.SourcePublicKey, IncomingAmount: 150, OutgoingAmount: 50}}
+	require.NoError(t, ValidateTransaction(tx, ValidationOptions{CurrentTick: 990, MaxTickOffset: 20, Source: source}))
+
+	unsigned := tx
+	unsigned.Signature = [64]byte{}
+	require.Error(t, ValidateTransaction(unsigned, ValidationOptions{}))
+
+	tampered := tx
+	tampered.Amount = 101
+	require.Error(t, ValidateTransaction(tampered, ValidationOptions{}))
+
+	require.Error(t, ValidateTransaction(tx, ValidationOptions{CurrentTick: 1000}), "tick not after current tick")
+	require.Error(t, ValidateTransaction(tx, ValidationOptions{CurrentTick: 900, MaxTickOffset: 20}), "tick too far ahead")
+
+	poor := &AddressInfo{AddressData: AddressData{PublicKey: tx.SourcePublicKey, IncomingAmount: 99}}
+	require.Error(t, ValidateTransaction(tx, ValidationOptions{Source: poor}))
+
+	other := &AddressInfo{AddressData: AddressData{PublicKey: tx.DestinationPublicKey, IncomingAmount: 1000}}
+	require.Error(t, ValidateTransaction(tx, ValidationOptions{Source: other}))
+
+	badSize := tx
+	badSize.InputSize = 1
+	require.Error(t, ValidateTransaction(badSize, ValidationOptions{}))
+}
+
+func TestValidateTransaction_SendMany(t *testing.T) {
+	var payload SendManyTransferPayload
+	require.NoError(t, payload.AddTransfer(SendManyTransfer{AddressID: validationTestDest, Amount: 40}))
+
+	tx, err := NewSendManyTransferTransaction(validationTestIdentity, 1000, payload)
+	require.NoError(t, err)
+	require.NoError(t, ValidateTransaction(signValidationTestTx(t, tx), ValidationOptions{}))
+
+	tx.Amount = 40
+	require.Error(t, ValidateTransaction(signValidationTestTx(t, tx), ValidationOptions{}), "amount without fee")
+}
+
+func TestValidateTransaction_QxTransfer(t *testing.T) {
+	tests := []struct {
+		name      string
+		assetName string
+		units     int64
+		fee       int64
+		wantErr   bool
+	}{
+		{name: "valid", assetName: "QX", units: 1, fee: 100},
+		{name: "empty asset name", assetName: "", units: 1, fee: 100, wantErr: true},
+		{name: "lower case asset name", assetName: "qx", units: 1, fee: 100, wantErr: true},
+		{name: "no units", assetName: "QX", units: 0, fee: 100, wantErr: true},
+		{name: "fee too low", assetName: "QX", units: 1, fee: 99, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := NewAssetTransferPayload(tt.assetName, validationTestDest, validationTestDest, tt.units)
+			require.NoError(t, err)
+
+			tx, err := NewAssetTransferTransaction(validationTestIdentity, 1000, tt.fee, payload)
+			require.NoError(t, err)
+
+			err = ValidateTransaction(signValidationTestTx(t, tx), ValidationOptions{QxTransferFee: 100})
+			if tt.wantErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}